@@ -0,0 +1,112 @@
+package main
+
+import (
+	"TaskTrackerCLI/internal/query"
+	"TaskTrackerCLI/internal/tasks"
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// bulkFlags are accepted by every command that takes a task selection.
+var bulkFlags = []string{"dry-run", "yes"}
+
+func parseQuery(args []string) (*query.Query, error) {
+	return query.Parse(strings.Join(args, " "), query.Options{Now: time.Now(), Statuses: workflow.States, Workflow: workflow})
+}
+
+// parseSelection reads a selection query. A plain task ID stays a plain ID,
+// so the command reports on that task as it always has.
+func parseSelection(args []string, trashed bool, failure string) tasks.Selection {
+	q, err := parseQuery(args)
+	if err != nil {
+		exitFatalError(failure, err)
+	}
+
+	if ID, ok := q.SingleID(); ok {
+		return tasks.Selection{IDs: []int{ID}, Trashed: trashed}
+	}
+
+	return tasks.Selection{IDs: q.IDs(), RangeIDs: q.RangeIDs(), Filter: tasks.Filter{Query: q, Workflow: workflow}, Trashed: trashed}
+}
+
+// runBulk applies fn to the selected tasks in a single update and prints
+// the result. It first previews the change on a copy of the tasks, which is
+// all --dry-run does, and asks before changing more than confirmAbove tasks.
+// The update selects the tasks again and refuses if they differ from the
+// preview.
+func runBulk(store tasks.Store, sel tasks.Selection, flags flagValues, action, failure string, fn tasks.BulkFunc) []tasks.Task {
+	list, err := store.Load()
+	if err != nil {
+		exitFatalError(failure, err)
+	}
+
+	IDs, err := sel.Resolve(list)
+	if err != nil {
+		exitFatalError(failure, err)
+	}
+
+	preview, err := tasks.Bulk(tasks.NewMemoryStore(list...), IDs, fn)
+	if err != nil {
+		exitFatalError(failure, err)
+	}
+
+	if flags.isSet("dry-run") {
+		printBulkResult(action, preview, true)
+		return nil
+	}
+
+	// A selection such as "-urgent" or "NOT 3" picks nearly every task, which
+	// is rarely what was meant, so it needs --yes however few tasks it picks.
+	if q, ok := sel.Filter.Query.(*query.Query); ok && q.OnlyExcludes() && !flags.isSet("yes") {
+		exitFatalError(failure, &tasks.ValidationError{
+			Field:   "yes",
+			Message: fmt.Sprintf("selection %q only excludes tasks, so %d tasks would be %s; pass --yes to confirm or --dry-run to preview", q.String(), len(preview), action),
+		})
+	}
+
+	if len(preview) > confirmAbove && !flags.isSet("yes") {
+		confirmBulk(action, preview, failure)
+	}
+
+	changed, err := tasks.BulkSelection(store, sel, IDs, fn)
+	if err != nil {
+		exitFatalError(failure, err)
+	}
+
+	if ID, ok := sel.Single(); ok {
+		i := slices.IndexFunc(changed, func(task tasks.Task) bool { return task.ID == ID })
+		changed[0], changed[i] = changed[i], changed[0]
+		printSubtreeResult(action, changed)
+		return changed
+	}
+
+	printBulkResult(action, changed, false)
+	return changed
+}
+
+// confirmBulk asks on the terminal whether to go ahead. Without a terminal
+// to ask on, it refuses and points at --yes.
+func confirmBulk(action string, preview []tasks.Task, failure string) {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		exitFatalError(failure, &tasks.ValidationError{
+			Field:   "yes",
+			Message: fmt.Sprintf("%d tasks would be %s; pass --yes to confirm or --dry-run to preview", len(preview), action),
+		})
+	}
+
+	IDs := taskIDs(preview)
+
+	fmt.Fprintf(os.Stderr, "%d tasks will be %s (IDs: %s). Continue? [y/N] ", len(preview), action, tasks.JoinIDs(IDs, ", "))
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+	default:
+		exitFatalError(failure, errors.New("cancelled"))
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type flagValues struct {
+	values map[string][]string
+	bools  map[string]bool
+}
+
+func (f flagValues) value(name string) string {
+	v := f.values[name]
+	if len(v) == 0 {
+		return ""
+	}
+
+	return v[len(v)-1]
+}
+
+func (f flagValues) all(name string) []string {
+	return f.values[name]
+}
+
+func (f flagValues) isSet(name string) bool {
+	return f.bools[name] || len(f.values[name]) > 0
+}
+
+// parseFlags splits command arguments into --flags and positional arguments.
+// Flags may appear anywhere; everything after a bare "--" is positional.
+func parseFlags(args []string, valueFlags []string, boolFlags []string) (flagValues, []string, error) {
+	flags := flagValues{values: map[string][]string{}, bools: map[string]bool{}}
+	var positional []string
+
+	isValue := map[string]bool{}
+	for _, name := range valueFlags {
+		isValue[name] = true
+	}
+	isBool := map[string]bool{}
+	for _, name := range boolFlags {
+		isBool[name] = true
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+
+		switch {
+		case isBool[name]:
+			if hasValue {
+				return flags, nil, fmt.Errorf("flag --%s does not take a value", name)
+			}
+			flags.bools[name] = true
+		case isValue[name]:
+			if !hasValue {
+				if i+1 >= len(args) {
+					return flags, nil, fmt.Errorf("flag --%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			flags.values[name] = append(flags.values[name], value)
+		default:
+			return flags, nil, fmt.Errorf("unknown flag --%s", name)
+		}
+	}
+
+	return flags, positional, nil
+}
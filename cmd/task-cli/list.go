@@ -0,0 +1,150 @@
+package main
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"strings"
+)
+
+var (
+	listValueFlags = []string{"priority", "due-before", "project", "sort", "limit", "offset", "columns"}
+	listBoolFlags  = []string{"overdue", "ready"}
+)
+
+// listRequest is what list was asked to show.
+type listRequest struct {
+	filter tasks.Filter
+	// statusOnly is set when a status is the only filter, so an empty
+	// result can name it.
+	statusOnly bool
+	sort       []tasks.SortKey
+	limit      int
+	layout     listLayout
+}
+
+// parseListArgs reads the arguments of list. A view passes the arguments it
+// was saved with and those given on the command line as separate groups:
+// the queries of all groups must match, and flags in later groups win.
+func parseListArgs(failure string, groups ...[]string) listRequest {
+	flags := flagValues{values: map[string][]string{}, bools: map[string]bool{}}
+	var status string
+	var queries []string
+
+	for _, group := range groups {
+		groupFlags, positional, err := parseFlags(group, listValueFlags, listBoolFlags)
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		for name, values := range groupFlags.values {
+			flags.values[name] = append(flags.values[name], values...)
+		}
+		for name := range groupFlags.bools {
+			flags.bools[name] = true
+		}
+
+		groupStatus, rest := splitStatusWords(positional)
+		if groupStatus != "" && status == "" {
+			status = groupStatus
+		} else if groupStatus != "" {
+			rest = append(rest, `status="`+groupStatus+`"`)
+		}
+		if len(rest) > 0 {
+			queries = append(queries, strings.Join(rest, " "))
+		}
+	}
+
+	req := listRequest{
+		filter: tasks.Filter{Status: status, Overdue: flags.isSet("overdue"), Ready: flags.isSet("ready"), Workflow: workflow},
+		layout: listLayout{Columns: defaultListColumns},
+	}
+	var err error
+
+	if len(queries) > 1 {
+		queries = []string{"(" + strings.Join(queries, ") (") + ")"}
+	}
+	if len(queries) > 0 {
+		if req.filter.Query, err = parseQuery(queries); err != nil {
+			exitFatalError(failure, err)
+		}
+	}
+
+	if flags.isSet("project") {
+		if req.filter.Project, err = tasks.NormalizeProject(flags.value("project")); err != nil {
+			exitFatalError(failure, err)
+		}
+	}
+
+	if flags.isSet("priority") {
+		value := flags.value("priority")
+		req.filter.PriorityAtLeast = strings.HasSuffix(value, "+")
+
+		if req.filter.Priority, err = tasks.ParsePriority(strings.TrimSuffix(value, "+")); err != nil {
+			exitFatalError(failure, err)
+		}
+	}
+
+	if flags.isSet("due-before") {
+		if req.filter.DueBefore, err = parseDue(flags.value("due-before"), false); err != nil {
+			exitFatalError(failure, err)
+		}
+	}
+
+	if flags.isSet("columns") {
+		if req.layout.Columns, err = parseColumns(flags.value("columns")); err != nil {
+			exitFatalError(failure, err)
+		}
+	}
+
+	if flags.isSet("sort") {
+		if req.sort, err = tasks.ParseSort(flags.value("sort")); err != nil {
+			exitFatalError(failure, err)
+		}
+	}
+
+	req.statusOnly = status != "" && len(queries) == 0 && !flags.isSet("project") && !flags.isSet("priority") &&
+		!flags.isSet("due-before") && !flags.isSet("overdue") && !flags.isSet("ready")
+
+	req.limit = parseCount(flags, "limit")
+	req.layout.Offset = parseCount(flags, "offset")
+
+	return req
+}
+
+// splitStatusWords picks out plain status words, as in "list in progress
+// +backend", so they need no status= in front. If the words other than
+// +tag and -tag do not name a status, status is empty and rest is args.
+func splitStatusWords(args []string) (status string, rest []string) {
+	var words []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+			rest = append(rest, arg)
+		} else {
+			words = append(words, arg)
+		}
+	}
+
+	if status = strings.Join(words, " "); len(words) == 0 || !workflow.HasState(status) {
+		return "", args
+	}
+
+	return status, rest
+}
+
+func runList(store tasks.Store, req listRequest) {
+	result, err := tasks.ListTasks(store, req.filter)
+	if err != nil {
+		exitFatalError("Error listing tasks", err)
+	}
+
+	// An explicit sort order replaces the nesting of subtasks under their
+	// parents, which would otherwise undo it.
+	if len(req.sort) > 0 {
+		tasks.SortTasks(workflow, result.Tasks, req.sort)
+		req.layout.Flat = true
+	}
+
+	req.layout.Matched = len(result.Tasks)
+	result.Tasks = tasks.Page(result.Tasks, req.layout.Offset, req.limit)
+
+	printTaskList(result, req)
+}
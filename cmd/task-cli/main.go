@@ -1,34 +1,85 @@
 package main
 
 import (
+	"TaskTrackerCLI/internal/config"
+	"TaskTrackerCLI/internal/dateparse"
+	"TaskTrackerCLI/internal/search"
+	"TaskTrackerCLI/internal/sqlitestore"
 	"TaskTrackerCLI/internal/tasks"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
-var validStatuses = map[string]bool{
-	"todo":        true,
-	"in progress": true,
-	"done":        true,
-}
+// Settings from the config file, loaded before any command runs.
+var (
+	workflow     = tasks.DefaultWorkflow()
+	withUIDs     bool
+	confirmAbove = config.DefaultConfirmAbove
+)
 
-const tasksFile = "tasks.json"
+const lockTimeoutEnv = "TASK_CLI_LOCK_TIMEOUT"
+
+type globalOptions struct {
+	file   string
+	output outputFormat
+}
 
 func showHelp() {
 	fmt.Println("Usage:")
-	fmt.Println("  task-cli add <task description>")
-	fmt.Println("  task-cli list [status]")
-	fmt.Println("  task-cli update <id> <new description>")
-	fmt.Println("  task-cli mark-in-progress <id>")
-	fmt.Println("  task-cli mark-done <id>")
-	fmt.Println("  task-cli delete <id>")
+	fmt.Println("  task-cli [--file <path>] [--output text|json|jsonl] <command> [arguments]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  task-cli add <task description> [+tag...] [--tag <tag>] [--priority <level>] [--due <date>] [--project <name>] [--parent <id>]")
+	fmt.Println("  task-cli list [status|query] [--priority <level>[+]] [--overdue] [--due-before <date>] [--project <name>] [--ready]")
+	fmt.Println("                [--sort [-]<field>,...] [--limit <n>] [--offset <n>] [--columns <column>,...]")
+	fmt.Println("  task-cli update <selection> [<new description>] [--due <date>|none] [--project <name>|none]")
+	fmt.Println("  task-cli mark-in-progress <selection...> [--force]")
+	fmt.Println("  task-cli mark-done <selection...> [--children refuse|cascade|reparent]")
+	fmt.Println("  task-cli delete <selection...> [--children refuse|cascade|reparent]")
+	fmt.Println("  task-cli status <selection> <status> [--children refuse|cascade|reparent] [--force]")
+	fmt.Println("  task-cli depend <selection> --on <id>[,<id>...] [--remove]")
+	fmt.Println("  task-cli priority <selection> <level>")
+	fmt.Println("  task-cli tag <selection> [+tag] [-tag]...")
+	fmt.Println("  task-cli trash")
+	fmt.Println("  task-cli restore <selection...>")
+	fmt.Println("  task-cli purge [--older-than <age>]")
+	fmt.Println("  task-cli history <id>")
+	fmt.Println("  task-cli view <name> [list arguments...]")
+	fmt.Println("  task-cli view save <name> <list arguments...>")
+	fmt.Println("  task-cli view list")
+	fmt.Println("  task-cli view delete <name>")
+	fmt.Println("  task-cli search <terms...> [--fuzzy] [--limit <n>]")
+	fmt.Println("  task-cli tags")
+	fmt.Println("  task-cli projects")
+	fmt.Println("  task-cli undo [--list]")
+	fmt.Println("  task-cli redo [--list]")
+	fmt.Println("  task-cli where")
+	fmt.Println("  task-cli migrate --to <sqlite|json>")
+	fmt.Println()
+	fmt.Println("Status values:")
+	for _, state := range workflow.States {
+		fmt.Println("  " + state)
+	}
+	fmt.Println("  (statuses and allowed transitions can be changed in the config file)")
+	fmt.Println()
+	fmt.Println("Priority levels:")
+	fmt.Println("  none, low, medium, high, critical (or 0-4)")
+	fmt.Println("  list --priority high+ shows high and critical tasks")
+	fmt.Println()
+	fmt.Println("List output:")
+	fmt.Println("  --sort by " + strings.Join(tasks.SortFields, ", "))
+	fmt.Println("  a field prefixed with - sorts descending, e.g. --sort -priority,created")
+	fmt.Println("  --columns from " + strings.Join(listColumnNames, ", "))
 	fmt.Println()
-	fmt.Println("Status values for list:")
-	fmt.Println("  todo")
-	fmt.Println("  in progress")
-	fmt.Println("  done")
+	fmt.Println("Dates:")
+	fmt.Println("  2026-01-31, \"2026-01-31 17:00\", today, tomorrow, friday, next friday,")
+	fmt.Println("  in 3 days, in 2 weeks")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println(`  task-cli add "Buy groceries"`)
@@ -38,39 +89,416 @@ func showHelp() {
 	fmt.Println(`  task-cli update 1 "Buy groceries and cook dinner"`)
 	fmt.Println(`  task-cli mark-in-progress 3`)
 	fmt.Println(`  task-cli mark-done 1`)
+	fmt.Println(`  task-cli mark-done 1,4,7-12`)
+	fmt.Println(`  task-cli mark-done status:todo +sprint42 --dry-run`)
 	fmt.Println(`  task-cli delete 2`)
+	fmt.Println(`  task-cli add "Fix login bug" --priority high`)
+	fmt.Println(`  task-cli priority 4 critical`)
+	fmt.Println(`  task-cli add "Send invoice" --due "next friday"`)
+	fmt.Println(`  task-cli update 2 --due "in 3 days"`)
+	fmt.Println(`  task-cli list --overdue`)
+	fmt.Println(`  task-cli add "Fix login bug +backend +urgent"`)
+	fmt.Println(`  task-cli tag 3 +blocked -urgent`)
+	fmt.Println(`  task-cli list +backend -blocked`)
+	fmt.Println(`  task-cli add "Quarantine flaky test" --project infra.ci.flaky`)
+	fmt.Println(`  task-cli list --project infra`)
+	fmt.Println(`  task-cli add "Write release notes" --parent 5`)
+	fmt.Println(`  task-cli delete 5 --children cascade`)
+	fmt.Println(`  task-cli depend 7 --on 5,6`)
+	fmt.Println(`  task-cli list --ready`)
+	fmt.Println(`  task-cli status 4 review`)
+	fmt.Println(`  task-cli history 4`)
+	fmt.Println(`  task-cli undo`)
+	fmt.Println(`  task-cli restore 2`)
+	fmt.Println(`  task-cli purge --older-than 30d`)
+	fmt.Println(`  task-cli list --sort -priority,created --limit 10`)
+	fmt.Println(`  task-cli list --columns id,status,updated,description`)
+	fmt.Println(`  task-cli search tls cert --fuzzy`)
+	fmt.Println(`  task-cli view save mine '+urgent OR project:web' --sort -priority`)
+	fmt.Println(`  task-cli view today`)
+	fmt.Println()
+	fmt.Println("Queries:")
+	fmt.Println("  Terms: IDs and ranges (1,4,7-12), +tag, -tag, overdue, ready, blocked and")
+	fmt.Println("  <field><op><value> with the fields id, status, priority, project, tag,")
+	fmt.Println("  description, created, updated, due and parent and the operators")
+	fmt.Println("  = != < <= > >= ~ (contains, or /regexp/) !~ and : (project:web includes")
+	fmt.Println("  sub-projects, priority:high+ means >=). Combine terms with AND, OR, NOT")
+	fmt.Println("  and parentheses; terms side by side must all match.")
+	fmt.Println(`  task-cli list 'status!=done AND (priority>=high OR +urgent)'`)
+	fmt.Println()
+	fmt.Println("Selecting tasks:")
+	fmt.Println("  Commands that change tasks take a query instead of a single ID. Quote it")
+	fmt.Println("  where a command expects one argument: task-cli tag \"status:todo +sprint42\" +next")
+	fmt.Println("  --dry-run shows what would change; changing more than " + strconv.Itoa(confirmAbove) + " tasks asks")
+	fmt.Println("  for confirmation unless --yes is given. A selection that only excludes tasks")
+	fmt.Println("  (-someday, NOT 3) always needs --yes. Ranges skip IDs that do not exist.")
+	fmt.Println()
+	fmt.Println("Dependencies:")
+	fmt.Println("  A task that depends on unfinished tasks is blocked: list marks it and")
+	fmt.Println("  mark-in-progress refuses it unless --force is given.")
+	fmt.Println()
+	fmt.Println("Subtasks:")
+	fmt.Println("  --children decides what mark-done, closing statuses and delete do with a task's subtasks:")
+	fmt.Println("  refuse (default) fails while there are any, cascade applies the command")
+	fmt.Println("  to them too, reparent moves them up to the task's own parent.")
+	fmt.Println()
+	fmt.Println("Task file:")
+	fmt.Println("  --file <path>, then " + config.FileEnv + ", then the nearest " + config.ProjectFileName)
+	fmt.Println("  in the current directory or its parents, then $XDG_DATA_HOME/task-cli/tasks.json.")
+	fmt.Println()
+	fmt.Println("Environment:")
+	fmt.Println("  " + config.FileEnv + "          path of the task file to use")
+	fmt.Println("  " + config.ConfigEnv + "        path of the config file (default $XDG_CONFIG_HOME/task-cli/config.json)")
+	fmt.Println("  " + lockTimeoutEnv + "  how long to wait for another task-cli process (default 5s)")
+	fmt.Println()
+	fmt.Println("Exit codes:")
+	fmt.Println("  0 success, 1 other error, 2 usage error, 3 task not found,")
+	fmt.Println("  4 invalid input, 5 corrupt task file, 6 task file locked,")
+	fmt.Println("  7 conflict with other tasks (e.g. unfinished subtasks, blocked task)")
 }
 
+const (
+	exitFailure  = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitInvalid  = 4
+	exitCorrupt  = 5
+	exitLocked   = 6
+	exitConflict = 7
+)
+
 func exitUsageError(message string) {
+	if output != outputText {
+		printError(message, exitUsage)
+		os.Exit(exitUsage)
+	}
+
 	fmt.Fprintln(os.Stderr, message)
 	fmt.Fprintln(os.Stderr)
 	showHelp()
-	os.Exit(1)
+	os.Exit(exitUsage)
 }
 
 func exitFatalError(message string, err error) {
+	code := exitCode(err)
+
+	if output != outputText {
+		printError(fmt.Sprintf("%s: %v", message, err), code)
+		os.Exit(code)
+	}
+
 	fmt.Fprintf(os.Stderr, "%s: %v\n", message, err)
-	os.Exit(1)
+	os.Exit(code)
 }
 
-func handleMarkStatus(status string, filename string, idStr string) error {
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound):
+		return exitNotFound
+	case errors.Is(err, tasks.ErrValidation):
+		return exitInvalid
+	case errors.Is(err, tasks.ErrCorruptStore):
+		return exitCorrupt
+	case errors.Is(err, tasks.ErrLocked):
+		return exitLocked
+	case errors.Is(err, tasks.ErrConflict):
+		return exitConflict
+	default:
+		return exitFailure
+	}
+}
+
+func parseTaskID(idStr string) (int, error) {
 	taskID, err := strconv.Atoi(idStr)
 	if err != nil {
-		return fmt.Errorf("invalid task ID %q: %w", idStr, err)
+		return 0, &tasks.ValidationError{Field: "id", Message: fmt.Sprintf("invalid task ID %q", idStr)}
+	}
+
+	return taskID, nil
+}
+
+// parseDue accepts anything dateparse understands; with allowNone set,
+// "none" yields the zero time so a due date can be cleared.
+func parseDue(value string, allowNone bool) (time.Time, error) {
+	if allowNone && strings.EqualFold(value, "none") {
+		return time.Time{}, nil
+	}
+
+	due, err := dateparse.Parse(value, time.Now())
+	if err != nil {
+		return time.Time{}, &tasks.ValidationError{Field: "due", Message: err.Error()}
+	}
+
+	return due, nil
+}
+
+// parseCount reads a flag that takes a number of tasks; unset means 0.
+func parseCount(flags flagValues, name string) int {
+	if !flags.isSet(name) {
+		return 0
+	}
+
+	n, err := strconv.Atoi(flags.value(name))
+	if err != nil || n < 0 {
+		exitUsageError(fmt.Sprintf("Error: --%s needs a number of tasks, got %q.", name, flags.value(name)))
+	}
+
+	return n
+}
+
+func parseChildPolicy(flags flagValues) tasks.ChildPolicy {
+	if !flags.isSet("children") {
+		return tasks.ChildrenRefuse
+	}
+
+	policy, err := tasks.ParseChildPolicy(flags.value("children"))
+	if err != nil {
+		exitUsageError("Error: " + err.Error() + ".")
+	}
+
+	return policy
+}
+
+// parseTaskIDs accepts IDs separated by commas, spaces, or both.
+func parseTaskIDs(values []string) ([]int, error) {
+	var IDs []int
+	for _, value := range values {
+		for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+			ID, err := parseTaskID(field)
+			if err != nil {
+				return nil, err
+			}
+			IDs = append(IDs, ID)
+		}
+	}
+
+	return IDs, nil
+}
+
+func handleSetStatus(store tasks.Store, sel tasks.Selection, status string, flags flagValues, failure string) {
+	opts := tasks.StatusOptions{Children: parseChildPolicy(flags), Force: flags.isSet("force")}
+
+	changed := runBulk(store, sel, flags, "updated", failure, func(store tasks.Store, ID int, now time.Time) ([]tasks.Task, error) {
+		opts.Now = now
+		task, err := tasks.SetStatus(store, workflow, ID, status, opts)
+		return []tasks.Task{task}, err
+	})
+
+	if opts.Force {
+		for _, task := range changed {
+			warnIfBlocked(store, task)
+		}
+	}
+}
+
+// journaledCommands are the commands that can be undone.
+var journaledCommands = map[string]bool{
+	"add":              true,
+	"update":           true,
+	"mark-in-progress": true,
+	"mark-done":        true,
+	"status":           true,
+	"delete":           true,
+	"priority":         true,
+	"tag":              true,
+	"depend":           true,
+	"restore":          true,
+	"purge":            true,
+}
+
+// commandLine reconstructs a command for the undo and view lists, quoting
+// arguments so that a shell would split it back into the same ones.
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		safe := arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("+-_.,:=/@", r))
+		}) < 0
+		if safe {
+			quoted[i] = arg
+		} else {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func loadConfig() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		exitFatalError("Error reading config file", err)
+	}
+
+	workflow = cfg.Workflow
+	withUIDs = cfg.UIDs
+	confirmAbove = cfg.ConfirmAbove
+}
+
+// parseGlobalFlags removes --file and --output from anywhere in args, up to
+// a bare "--", so they work both before and after the command name.
+func parseGlobalFlags(args []string) (globalOptions, []string, error) {
+	opts := globalOptions{output: outputText}
+	var rest []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !strings.HasPrefix(arg, "--") || (name != "file" && name != "output") {
+			rest = append(rest, arg)
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return opts, nil, fmt.Errorf("flag --%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		if value == "" {
+			return opts, nil, fmt.Errorf("flag --%s requires a value", name)
+		}
+
+		switch name {
+		case "file":
+			opts.file = value
+		case "output":
+			format, err := parseOutputFormat(value)
+			if err != nil {
+				return opts, nil, err
+			}
+			opts.output = format
+		}
+	}
+
+	return opts, rest, nil
+}
+
+func resolveLocation(opts globalOptions) config.Location {
+	cwd, err := os.Getwd()
+	if err != nil {
+		exitFatalError("Error: cannot determine current directory", err)
+	}
+
+	location, err := config.ResolveTasksFile(opts.file, os.Getenv, cwd)
+	if err != nil {
+		exitFatalError("Error locating task file", err)
+	}
+
+	if location.Source == config.SourceDefault {
+		if err := os.MkdirAll(filepath.Dir(location.Path), 0o755); err != nil {
+			exitFatalError("Error creating data directory", err)
+		}
+	}
+
+	return location
+}
+
+func lockTimeout() time.Duration {
+	value := os.Getenv(lockTimeoutEnv)
+	if value == "" {
+		return tasks.DefaultLockTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		exitUsageError(fmt.Sprintf("Error: invalid %s value %q.", lockTimeoutEnv, value))
+	}
+
+	return timeout
+}
+
+func openStore(path string) tasks.Store {
+	if config.IsSQLite(path) {
+		store, err := sqlitestore.Open(path, lockTimeout())
+		if err != nil {
+			exitFatalError("Error opening task database", err)
+		}
+		return store
+	}
+
+	store := tasks.NewJSONStore(path)
+	store.LockTimeout = lockTimeout()
+	return store
+}
+
+// closeStore closes stores that hold a database connection.
+func closeStore(store tasks.Store) error {
+	if closer, ok := store.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func handleMigrate(location config.Location, args []string) {
+	flags, _, err := parseFlags(args, []string{"to"}, nil)
+	if err != nil {
+		exitUsageError("Error: " + err.Error() + ".")
 	}
 
-	switch status {
-	case "in progress":
-		return tasks.MarkTaskInProgress(filename, taskID)
-	case "done":
-		return tasks.MarkTaskDone(filename, taskID)
+	var toSQLite bool
+	switch flags.value("to") {
+	case "sqlite":
+		toSQLite = true
+	case "json":
+		toSQLite = false
+	case "":
+		exitUsageError("Error: missing --to <sqlite|json>.")
 	default:
-		return fmt.Errorf("unsupported status %q", status)
+		exitUsageError(fmt.Sprintf("Error: unknown backend %q.\nAllowed backends: sqlite, json.", flags.value("to")))
+	}
+
+	if config.IsSQLite(location.Path) == toSQLite {
+		exitUsageError(fmt.Sprintf("Error: %s already uses the %s backend.", location.Path, flags.value("to")))
+	}
+
+	target := config.SiblingPath(location.Path, toSQLite)
+	if _, err := os.Stat(target); err == nil {
+		exitFatalError("Error migrating tasks", fmt.Errorf("%s already exists", target))
+	}
+
+	src, dst := openStore(location.Path), openStore(target)
+	n, err := tasks.CopyTasks(dst, src)
+	closeStore(src)
+	if closeErr := closeStore(dst); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Leave no half-written target behind, so the migration can simply
+		// be run again.
+		for _, name := range []string{target, target + "-wal", target + "-shm", tasks.LockFile(target), tasks.BackupFile(target)} {
+			os.Remove(name)
+		}
+		exitFatalError("Error migrating tasks", err)
+	}
+
+	if output != outputText {
+		printJSON(migrateResult{Action: "migrated", From: location.Path, To: target, Count: n})
+		return
+	}
+
+	fmt.Printf("Migrated %d tasks from %s to %s\n", n, location.Path, target)
+	if location.Source == config.SourceFlag || location.Source == config.SourceEnv {
+		fmt.Printf("Point --file or %s at %s to use it.\n", config.FileEnv, target)
 	}
 }
 
 func main() {
-	args := os.Args[1:]
+	opts, args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		exitUsageError("Error: " + err.Error() + ".")
+	}
+	output = opts.output
+	loadConfig()
 
 	if len(args) == 0 {
 		showHelp()
@@ -83,88 +511,381 @@ func main() {
 	}
 
 	command := args[0]
+	location := resolveLocation(opts)
+
+	switch command {
+	case "where":
+		printLocation(location)
+		return
+	case "migrate":
+		handleMigrate(location, args[1:])
+		return
+	}
+
+	store := openStore(location.Path)
+	journal := tasks.NewJournal(tasks.JournalFile(location.Path))
+	journal.LockTimeout = lockTimeout()
+
+	if journaledCommands[command] {
+		store = journal.Wrap(store, commandLine(args))
+	}
 
 	switch command {
+	case "undo", "redo":
+		flags, positional, err := parseFlags(args[1:], nil, []string{"list"})
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		} else if len(positional) > 0 {
+			exitUsageError(fmt.Sprintf("Error: unexpected argument %q.", positional[0]))
+		}
+
+		if flags.isSet("list") {
+			undo, redo, err := journal.Entries()
+			if err != nil {
+				exitFatalError("Error reading undo history", err)
+			}
+
+			if command == "undo" {
+				printJournal(undo, "undo")
+			} else {
+				printJournal(redo, "redo")
+			}
+			return
+		}
+
+		replay, action := journal.Undo, "undone"
+		if command == "redo" {
+			replay, action = journal.Redo, "redone"
+		}
+
+		entry, err := replay(store)
+		if err != nil {
+			exitFatalError("Error running "+command, err)
+		}
+
+		printJournalResult(action, entry)
 	case "add":
-		if len(args) < 2 {
+		flags, positional, err := parseFlags(args[1:], []string{"priority", "due", "tag", "project", "parent"}, nil)
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		taskText, tags := tasks.ExtractTags(strings.Join(positional, " "))
+		if taskText == "" {
 			exitUsageError("Error: missing task description.")
 		}
 
-		taskText := strings.Join(args[1:], " ")
+		opts := tasks.AddOptions{
+			Tags:     append(tags, flags.all("tag")...),
+			Project:  flags.value("project"),
+			Workflow: workflow,
+			WithUID:  withUIDs,
+		}
+		if flags.isSet("priority") {
+			if opts.Priority, err = tasks.ParsePriority(flags.value("priority")); err != nil {
+				exitFatalError("Error adding task", err)
+			}
+		}
+
+		if flags.isSet("due") {
+			if opts.DueAt, err = parseDue(flags.value("due"), false); err != nil {
+				exitFatalError("Error adding task", err)
+			}
+		}
+
+		if flags.isSet("parent") {
+			if opts.ParentID, err = parseTaskID(flags.value("parent")); err != nil {
+				exitFatalError("Error adding task", err)
+			}
+		}
 
-		if err := tasks.AddTask(tasksFile, taskText); err != nil {
+		task, err := tasks.AddTask(store, taskText, opts)
+		if err != nil {
 			exitFatalError("Error adding task", err)
 		}
+
+		printTaskResult("added", task)
 	case "list":
-		var status string
-		if len(args) > 1 {
-			status = strings.Join(args[1:], " ")
+		runList(store, parseListArgs("Error listing tasks", args[1:]))
+	case "depend":
+		flags, positional, err := parseFlags(args[1:], []string{"on"}, append([]string{"remove"}, bulkFlags...))
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
 
-			if !validStatuses[status] {
-				exitUsageError("Error: invalid task status.\nAllowed statuses: todo, in progress, done.")
-			}
-		} else {
-			status = ""
+		if len(positional) < 1 {
+			exitUsageError("Error: missing task ID.")
+		} else if len(positional) > 1 {
+			exitUsageError(fmt.Sprintf("Error: unexpected argument %q.", positional[1]))
+		} else if !flags.isSet("on") {
+			exitUsageError("Error: missing --on <id>.")
 		}
 
-		if err := tasks.ListTasks(tasksFile, status); err != nil {
-			exitFatalError("Error listing tasks", err)
+		const failure = "Error updating dependencies"
+		sel := parseSelection(positional[:1], false, failure)
+
+		on, err := parseTaskIDs(flags.all("on"))
+		if err != nil {
+			exitFatalError(failure, err)
 		}
-	case "update":
-		if len(args) < 2 {
-			exitUsageError("Error: missing task ID and description.")
-		} else if len(args) < 3 {
-			exitUsageError("Error: missing task description.")
+
+		edit := tasks.AddDependencies
+		if flags.isSet("remove") {
+			edit = tasks.RemoveDependencies
 		}
 
-		idStr := args[1]
-		taskID, err := strconv.Atoi(idStr)
+		runBulk(store, sel, flags, "updated", failure, func(store tasks.Store, ID int, _ time.Time) ([]tasks.Task, error) {
+			task, err := edit(store, ID, on)
+			return []tasks.Task{task}, err
+		})
+	case "tag":
+		flags, positional, err := parseFlags(args[1:], nil, bulkFlags)
 		if err != nil {
-			exitFatalError("Error: invalid task ID", err)
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		if len(positional) < 1 {
+			exitUsageError("Error: missing task ID and tags.")
+		} else if len(positional) < 2 {
+			exitUsageError("Error: missing tags (use +tag to add, -tag to remove).")
 		}
 
-		newDescription := strings.Join(args[2:], " ")
+		const failure = "Error tagging task"
+		sel := parseSelection(positional[:1], false, failure)
 
-		if err := tasks.UpdateTask(tasksFile, taskID, newDescription); err != nil {
-			exitFatalError("Error updating task", err)
+		var add, remove []string
+		for _, arg := range positional[1:] {
+			switch {
+			case strings.HasPrefix(arg, "-"):
+				remove = append(remove, arg[1:])
+			default:
+				add = append(add, arg)
+			}
 		}
-	case "mark-in-progress":
-		if len(args) < 2 {
+
+		runBulk(store, sel, flags, "updated", failure, func(store tasks.Store, ID int, _ time.Time) ([]tasks.Task, error) {
+			task, err := tasks.EditTags(store, ID, add, remove)
+			return []tasks.Task{task}, err
+		})
+	case "trash":
+		trashed, err := tasks.Trash(store)
+		if err != nil {
+			exitFatalError("Error listing trash", err)
+		}
+
+		printTrash(trashed)
+	case "restore":
+		flags, positional, err := parseFlags(args[1:], nil, bulkFlags)
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		if len(positional) < 1 {
 			exitUsageError("Error: missing task ID.")
 		}
 
-		idStr := args[1]
+		const failure = "Error restoring task"
+		runBulk(store, parseSelection(positional, true, failure), flags, "restored", failure, func(store tasks.Store, ID int, _ time.Time) ([]tasks.Task, error) {
+			return tasks.RestoreTask(store, ID)
+		})
+	case "purge":
+		flags, positional, err := parseFlags(args[1:], []string{"older-than"}, nil)
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		} else if len(positional) > 0 {
+			exitUsageError(fmt.Sprintf("Error: unexpected argument %q.", positional[0]))
+		}
+
+		var olderThan time.Duration
+		if flags.isSet("older-than") {
+			if olderThan, err = dateparse.ParseDuration(flags.value("older-than")); err != nil {
+				exitFatalError("Error purging trash", &tasks.ValidationError{Field: "older-than", Message: err.Error()})
+			}
+		}
 
-		err := handleMarkStatus("in progress", tasksFile, idStr)
+		purged, err := tasks.PurgeTasks(store, olderThan, time.Now())
 		if err != nil {
-			exitFatalError("Error marking task 'in progress'", err)
+			exitFatalError("Error purging trash", err)
 		}
-	case "mark-done":
+
+		printPurgeResult(purged)
+	case "history":
 		if len(args) < 2 {
 			exitUsageError("Error: missing task ID.")
 		}
 
-		idStr := args[1]
+		taskID, err := parseTaskID(args[1])
+		if err != nil {
+			exitFatalError("Error showing history", err)
+		}
 
-		err := handleMarkStatus("done", tasksFile, idStr)
+		task, err := store.Get(taskID)
 		if err != nil {
-			exitFatalError("Error marking task 'done'", err)
+			exitFatalError("Error showing history", err)
 		}
-	case "delete":
-		if len(args) < 2 {
+
+		printHistory(task)
+	case "view":
+		handleView(store, args[1:])
+	case "search":
+		flags, positional, err := parseFlags(args[1:], []string{"limit"}, []string{"fuzzy"})
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		} else if len(positional) == 0 {
+			exitUsageError("Error: missing search terms.")
+		}
+
+		limit := parseCount(flags, "limit")
+
+		results, err := search.Search(store, strings.Join(positional, " "), search.Options{Fuzzy: flags.isSet("fuzzy")})
+		if err != nil {
+			exitFatalError("Error searching tasks", err)
+		}
+
+		matched := len(results)
+		if limit > 0 && limit < matched {
+			results = results[:limit]
+		}
+
+		printSearchResults(results, matched)
+	case "tags":
+		counts, err := tasks.TagCounts(store)
+		if err != nil {
+			exitFatalError("Error listing tags", err)
+		}
+
+		printTagCounts(counts)
+	case "projects":
+		summaries, err := tasks.Projects(store)
+		if err != nil {
+			exitFatalError("Error listing projects", err)
+		}
+
+		printProjects(summaries, workflow.States)
+	case "priority":
+		flags, positional, err := parseFlags(args[1:], nil, bulkFlags)
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		if len(positional) < 1 {
+			exitUsageError("Error: missing task ID and priority.")
+		} else if len(positional) < 2 {
+			exitUsageError("Error: missing priority.")
+		}
+
+		const failure = "Error setting priority"
+		sel := parseSelection(positional[:1], false, failure)
+
+		priority, err := tasks.ParsePriority(positional[1])
+		if err != nil {
+			exitFatalError(failure, err)
+		}
+
+		runBulk(store, sel, flags, "updated", failure, func(store tasks.Store, ID int, _ time.Time) ([]tasks.Task, error) {
+			task, err := tasks.SetPriority(store, ID, priority)
+			return []tasks.Task{task}, err
+		})
+	case "update":
+		flags, positional, err := parseFlags(args[1:], []string{"due", "project"}, bulkFlags)
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		if len(positional) < 1 {
+			exitUsageError("Error: missing task ID and description.")
+		} else if len(positional) < 2 && !flags.isSet("due") && !flags.isSet("project") {
+			exitUsageError("Error: missing task description.")
+		}
+
+		const failure = "Error updating task"
+		sel := parseSelection(positional[:1], false, failure)
+
+		var changes tasks.TaskChanges
+		if len(positional) > 1 {
+			if _, ok := sel.Single(); !ok {
+				exitUsageError("Error: a new description can only be given for a single task ID.")
+			}
+
+			newDescription := strings.Join(positional[1:], " ")
+			changes.Description = &newDescription
+		}
+
+		if flags.isSet("due") {
+			due, err := parseDue(flags.value("due"), true)
+			if err != nil {
+				exitFatalError(failure, err)
+			}
+			changes.DueAt = &due
+		}
+
+		if flags.isSet("project") {
+			project := flags.value("project")
+			if strings.EqualFold(project, "none") {
+				project = ""
+			}
+			changes.Project = &project
+		}
+
+		runBulk(store, sel, flags, "updated", failure, func(store tasks.Store, ID int, _ time.Time) ([]tasks.Task, error) {
+			task, err := tasks.UpdateTask(store, ID, changes)
+			return []tasks.Task{task}, err
+		})
+	case "mark-in-progress":
+		flags, positional, err := parseFlags(args[1:], nil, append([]string{"force"}, bulkFlags...))
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		if len(positional) < 1 {
+			exitUsageError("Error: missing task ID.")
+		}
+
+		const failure = "Error marking task 'in progress'"
+		handleSetStatus(store, parseSelection(positional, false, failure), "in progress", flags, failure)
+	case "mark-done":
+		flags, positional, err := parseFlags(args[1:], []string{"children"}, bulkFlags)
+		if err != nil {
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		if len(positional) < 1 {
 			exitUsageError("Error: missing task ID.")
 		}
 
-		idStr := args[1]
-		taskID, err := strconv.Atoi(idStr)
+		const failure = "Error marking task 'done'"
+		handleSetStatus(store, parseSelection(positional, false, failure), "done", flags, failure)
+	case "status":
+		flags, positional, err := parseFlags(args[1:], []string{"children"}, append([]string{"force"}, bulkFlags...))
 		if err != nil {
-			exitFatalError("Error: invalid task ID", err)
+			exitUsageError("Error: " + err.Error() + ".")
 		}
 
-		err = tasks.DeleteTask(tasksFile, taskID)
+		if len(positional) < 1 {
+			exitUsageError("Error: missing task ID and status.")
+		} else if len(positional) < 2 {
+			exitUsageError("Error: missing status.\nAllowed statuses: " + strings.Join(workflow.States, ", ") + ".")
+		}
+
+		const failure = "Error changing task status"
+		handleSetStatus(store, parseSelection(positional[:1], false, failure), strings.Join(positional[1:], " "), flags, failure)
+	case "delete":
+		flags, positional, err := parseFlags(args[1:], []string{"children"}, bulkFlags)
 		if err != nil {
-			exitFatalError("Error deleting task", err)
+			exitUsageError("Error: " + err.Error() + ".")
+		}
+
+		if len(positional) < 1 {
+			exitUsageError("Error: missing task ID.")
 		}
+
+		const failure = "Error deleting task"
+		sel := parseSelection(positional, false, failure)
+		policy := parseChildPolicy(flags)
+
+		runBulk(store, sel, flags, "deleted", failure, func(store tasks.Store, ID int, now time.Time) ([]tasks.Task, error) {
+			return tasks.DeleteTaskAt(store, ID, policy, now)
+		})
 	default:
 		exitUsageError("Invalid command: " + command)
 	}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	cases := []struct {
+		name       string
+		args       []string
+		values     map[string][]string
+		bools      []string
+		positional []string
+		wantErr    bool
+	}{
+		{
+			name:       "Splits flags from positional arguments anywhere",
+			args:       []string{"a", "--project", "web", "b", "--force"},
+			values:     map[string][]string{"project": {"web"}},
+			bools:      []string{"force"},
+			positional: []string{"a", "b"},
+		},
+		{
+			name:   "Accepts --name=value and repeated value flags",
+			args:   []string{"--project=web", "--project", "api"},
+			values: map[string][]string{"project": {"web", "api"}},
+		},
+		{
+			name:       "Treats everything after -- as positional",
+			args:       []string{"a", "--", "--force", "-x"},
+			positional: []string{"a", "--force", "-x"},
+		},
+		{
+			name:       "Keeps single-dash arguments positional",
+			args:       []string{"-1", "+tag"},
+			positional: []string{"-1", "+tag"},
+		},
+		{name: "Rejects unknown flags", args: []string{"--nope"}, wantErr: true},
+		{name: "Rejects a value for a bool flag", args: []string{"--force=yes"}, wantErr: true},
+		{name: "Rejects a value flag without a value", args: []string{"--project"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			flags, positional, err := parseFlags(c.args, []string{"project"}, []string{"force"})
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			for name, want := range c.values {
+				if got := flags.all(name); !slices.Equal(got, want) {
+					t.Errorf("Expected --%s %v, got %v", name, want, got)
+				}
+			}
+			for _, name := range c.bools {
+				if !flags.isSet(name) {
+					t.Errorf("Expected --%s to be set", name)
+				}
+			}
+			if !slices.Equal(positional, c.positional) {
+				t.Errorf("Expected positional %q, got %q", c.positional, positional)
+			}
+		})
+	}
+}
+
+func TestParseGlobalFlags(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		opts    globalOptions
+		rest    []string
+		wantErr bool
+	}{
+		{
+			name: "Reads flags before the command",
+			args: []string{"--file", "t.json", "--output=json", "list"},
+			opts: globalOptions{file: "t.json", output: outputJSON},
+			rest: []string{"list"},
+		},
+		{
+			name: "Reads flags after the command",
+			args: []string{"list", "--project", "web", "--file=t.json"},
+			opts: globalOptions{file: "t.json", output: outputText},
+			rest: []string{"list", "--project", "web"},
+		},
+		{
+			name: "Leaves flags after -- alone",
+			args: []string{"add", "--", "--file", "x"},
+			opts: globalOptions{output: outputText},
+			rest: []string{"add", "--", "--file", "x"},
+		},
+		{name: "Rejects a missing value", args: []string{"list", "--file"}, wantErr: true},
+		{name: "Rejects an empty value", args: []string{"--file=", "list"}, wantErr: true},
+		{name: "Rejects an unknown output format", args: []string{"--output", "xml"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts, rest, err := parseGlobalFlags(c.args)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if opts != c.opts {
+				t.Errorf("Expected options %+v, got %+v", c.opts, opts)
+			}
+			if !slices.Equal(rest, c.rest) {
+				t.Errorf("Expected rest %q, got %q", c.rest, rest)
+			}
+		})
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"task-cli", "add", "--project=web", "+tag"}, "task-cli add --project=web +tag"},
+		{[]string{"add", "Buy milk"}, "add 'Buy milk'"},
+		{[]string{"add", ""}, "add ''"},
+		{[]string{"add", "it's"}, `add 'it'\''s'`},
+	}
+
+	for _, c := range cases {
+		if got := commandLine(c.args); got != c.want {
+			t.Errorf("commandLine(%q): expected %q, got %q", c.args, c.want, got)
+		}
+	}
+}
+
+func TestSplitStatusWords(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		status string
+		rest   []string
+	}{
+		{"Joins a multi-word status", []string{"in", "progress", "+backend"}, "in progress", []string{"+backend"}},
+		{"Reads a single-word status", []string{"-web", "done"}, "done", []string{"-web"}},
+		{"Returns the arguments when the words are not a status", []string{"later", "+x"}, "", []string{"later", "+x"}},
+		{"Returns the arguments when there are no words", []string{"+x"}, "", []string{"+x"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			status, rest := splitStatusWords(c.args)
+			if status != c.status {
+				t.Errorf("Expected status %q, got %q", c.status, status)
+			}
+			if !slices.Equal(rest, c.rest) {
+				t.Errorf("Expected rest %q, got %q", c.rest, rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,828 @@
+package main
+
+import (
+	"TaskTrackerCLI/internal/config"
+	"TaskTrackerCLI/internal/search"
+	"TaskTrackerCLI/internal/tasks"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type outputFormat string
+
+const (
+	outputText  outputFormat = "text"
+	outputJSON  outputFormat = "json"
+	outputJSONL outputFormat = "jsonl"
+)
+
+var output = outputText
+
+func parseOutputFormat(value string) (outputFormat, error) {
+	switch format := outputFormat(value); format {
+	case outputText, outputJSON, outputJSONL:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (allowed: text, json, jsonl)", value)
+	}
+}
+
+type taskResult struct {
+	Action   string       `json:"action"`
+	Task     tasks.Task   `json:"task"`
+	Subtasks []tasks.Task `json:"subtasks,omitempty"`
+	// Parents were restored along with a subtask.
+	Parents []tasks.Task `json:"parents,omitempty"`
+}
+
+type locationResult struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+type migrateResult struct {
+	Action string `json:"action"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Count  int    `json:"count"`
+}
+
+type historyResult struct {
+	TaskID           int           `json:"task_id"`
+	Events           []tasks.Event `json:"events"`
+	CycleTimeSeconds *float64      `json:"cycle_time_seconds,omitempty"`
+}
+
+type journalResult struct {
+	Action  string         `json:"action"`
+	Command string         `json:"command"`
+	At      time.Time      `json:"at"`
+	Changes []tasks.Change `json:"changes"`
+}
+
+type purgeResult struct {
+	Action string       `json:"action"`
+	Count  int          `json:"count"`
+	Tasks  []tasks.Task `json:"tasks"`
+}
+
+type bulkResult struct {
+	Action string       `json:"action"`
+	DryRun bool         `json:"dry_run,omitempty"`
+	Count  int          `json:"count"`
+	Tasks  []tasks.Task `json:"tasks"`
+}
+
+type viewEntry struct {
+	Name    string   `json:"name"`
+	Args    []string `json:"args"`
+	Builtin bool     `json:"builtin,omitempty"`
+}
+
+type viewResult struct {
+	Action string    `json:"action"`
+	View   viewEntry `json:"view"`
+}
+
+type errorResult struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	ExitCode int    `json:"exit_code"`
+}
+
+var taskResultMessages = map[string]string{
+	"added":    "Task added successfully (ID: %d)\n",
+	"updated":  "Task updated successfully (ID: %d)\n",
+	"deleted":  "Task moved to trash (ID: %d)\n",
+	"restored": "Task restored successfully (ID: %d)\n",
+}
+
+func printTaskResult(action string, task tasks.Task) {
+	if output != outputText {
+		printJSON(taskResult{Action: action, Task: task})
+		return
+	}
+
+	fmt.Printf(taskResultMessages[action], task.ID)
+}
+
+var bulkResultMessages = map[string]string{
+	"updated":  "Updated %d tasks (IDs: %s)\n",
+	"deleted":  "Moved %d tasks to trash (IDs: %s)\n",
+	"restored": "Restored %d tasks (IDs: %s)\n",
+}
+
+// printBulkResult reports the tasks a bulk command changed, or with dryRun
+// the tasks it would change and how they would look.
+func printBulkResult(action string, changed []tasks.Task, dryRun bool) {
+	if output != outputText {
+		if changed == nil {
+			changed = []tasks.Task{}
+		}
+		printJSON(bulkResult{Action: action, DryRun: dryRun, Count: len(changed), Tasks: changed})
+		return
+	}
+
+	if len(changed) == 0 {
+		fmt.Println("No tasks match the selection.")
+		return
+	}
+
+	if dryRun {
+		fmt.Printf("%d tasks would be %s (dry run):\n", len(changed), action)
+		for _, task := range changed {
+			fmt.Printf("  %-4d %-12s %s\n", task.ID, task.Status, describeTask(task))
+		}
+		return
+	}
+
+	IDs := taskIDs(changed)
+	fmt.Printf(bulkResultMessages[action], len(changed), tasks.JoinIDs(IDs, ", "))
+}
+
+// printSubtreeResult reports a deleted or restored task along with the
+// subtasks and, for a restored subtask, the parents that went with it.
+func printSubtreeResult(action string, affected []tasks.Task) {
+	task := affected[0]
+	subtasks, parents := splitSubtree(affected)
+
+	if output != outputText {
+		printJSON(taskResult{Action: action, Task: task, Subtasks: subtasks, Parents: parents})
+		return
+	}
+
+	fmt.Printf(taskResultMessages[action], task.ID)
+	if len(subtasks) > 0 {
+		fmt.Printf("Also %s %s\n", action, countOf(len(subtasks), "subtask"))
+	}
+	if len(parents) > 0 {
+		fmt.Printf("Also %s %s\n", action, countOf(len(parents), "parent"))
+	}
+}
+
+func printTrash(trashed []tasks.Task) {
+	switch output {
+	case outputJSON:
+		printJSON(trashed)
+		return
+	case outputJSONL:
+		for _, task := range trashed {
+			printJSON(task)
+		}
+		return
+	}
+
+	if len(trashed) == 0 {
+		fmt.Println("Trash is empty.")
+		return
+	}
+
+	fmt.Printf("%-4s %-17s %-12s %s\n", "ID", "Deleted", "Status", "Description")
+	for _, task := range trashed {
+		fmt.Printf("%-4d %-17s %-12s %s\n", task.ID, task.DeletedAt.Format("2006-01-02 15:04"), task.Status, describeTask(task))
+	}
+}
+
+func printPurgeResult(purged []tasks.Task) {
+	if output != outputText {
+		if purged == nil {
+			purged = []tasks.Task{}
+		}
+		printJSON(purgeResult{Action: "purged", Count: len(purged), Tasks: purged})
+		return
+	}
+
+	switch len(purged) {
+	case 0:
+		fmt.Println("Nothing to purge.")
+	case 1:
+		fmt.Println("Purged 1 task from the trash")
+	default:
+		fmt.Printf("Purged %d tasks from the trash\n", len(purged))
+	}
+}
+
+// listLayout says how list shows its table: which columns, whether subtasks
+// are nested under their parents, and which page of the matches it is.
+type listLayout struct {
+	Columns []string
+	Flat    bool
+	Offset  int
+	Matched int
+}
+
+// listColumn is one column of the list table. The last column shown is not
+// padded, so it can hold long descriptions.
+type listColumn struct {
+	header string
+	width  int
+	value  func(entry tasks.TreeEntry, result tasks.ListResult, now time.Time) string
+}
+
+var listColumns = map[string]listColumn{
+	"id": {"ID", 4, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		return strconv.Itoa(entry.Task.ID)
+	}},
+	"overdue": {"!", 1, func(entry tasks.TreeEntry, _ tasks.ListResult, now time.Time) string {
+		return overdueMarker(entry.Task, now)
+	}},
+	"status": {"Status", 12, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		return entry.Task.Status
+	}},
+	"priority": {"Priority", 9, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		return priorityLabel(entry.Task.Priority)
+	}},
+	"due": {"Due", 17, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		return dueLabel(entry.Task.DueAt)
+	}},
+	"created": {"Created", 17, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		return timeLabel(entry.Task.CreatedAt)
+	}},
+	"updated": {"Updated", 17, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		return timeLabel(entry.Task.UpdatedAt)
+	}},
+	"project": {"Project", 16, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		return projectLabel(entry.Task.Project)
+	}},
+	"parent": {"Parent", 6, func(entry tasks.TreeEntry, _ tasks.ListResult, _ time.Time) string {
+		if entry.Task.ParentID == 0 {
+			return "-"
+		}
+		return strconv.Itoa(entry.Task.ParentID)
+	}},
+	"description": {"Description", 40, func(entry tasks.TreeEntry, result tasks.ListResult, _ time.Time) string {
+		task := entry.Task
+		return treeIndent(entry.Depth) + describeTask(task) + progressLabel(result.Progress[task.ID]) + blockedLabel(result.Blocked[task.ID])
+	}},
+}
+
+// listColumnNames lists the columns in the order help shows them.
+var listColumnNames = []string{"id", "overdue", "status", "priority", "due", "created", "updated", "project", "parent", "description"}
+
+var defaultListColumns = []string{"id", "overdue", "status", "priority", "due", "created", "project", "description"}
+
+func parseColumns(value string) ([]string, error) {
+	var columns []string
+	for name := range strings.SplitSeq(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := listColumns[name]; !ok {
+			return nil, &tasks.ValidationError{
+				Field:   "columns",
+				Message: fmt.Sprintf("unknown column %q (allowed: %s)", name, strings.Join(listColumnNames, ", ")),
+			}
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, nil
+}
+
+func printTaskList(result tasks.ListResult, req listRequest) {
+	layout := req.layout
+	switch output {
+	case outputJSON:
+		printJSON(result.Tasks)
+		return
+	case outputJSONL:
+		for _, task := range result.Tasks {
+			printJSON(task)
+		}
+		return
+	}
+
+	if result.Total == 0 {
+		fmt.Println("No tasks found.")
+		return
+	}
+
+	if layout.Matched == 0 {
+		if req.statusOnly {
+			fmt.Printf("No tasks with status %q found.\n", req.filter.Status)
+		} else {
+			fmt.Println("No matching tasks found.")
+		}
+		return
+	}
+
+	if len(result.Tasks) == 0 {
+		fmt.Printf("No tasks past offset %d (%d matching).\n", layout.Offset, layout.Matched)
+		return
+	}
+
+	entries := tasks.TreeOrder(result.Tasks)
+	if layout.Flat {
+		entries = make([]tasks.TreeEntry, len(result.Tasks))
+		for i, task := range result.Tasks {
+			entries[i] = tasks.TreeEntry{Task: task}
+		}
+	}
+
+	now := time.Now()
+	printRow(layout.Columns, func(column listColumn) string { return column.header })
+	for _, entry := range entries {
+		printRow(layout.Columns, func(column listColumn) string { return column.value(entry, result, now) })
+	}
+
+	if len(result.Tasks) < layout.Matched {
+		fmt.Printf("Showing %d-%d of %d matching tasks.\n", layout.Offset+1, layout.Offset+len(result.Tasks), layout.Matched)
+	}
+}
+
+func printRow(columns []string, cell func(column listColumn) string) {
+	var row strings.Builder
+	for i, name := range columns {
+		column := listColumns[name]
+		if i == len(columns)-1 {
+			row.WriteString(cell(column))
+			break
+		}
+		fmt.Fprintf(&row, "%-*s ", column.width, cell(column))
+	}
+
+	fmt.Println(row.String())
+}
+
+func describeTask(task tasks.Task) string {
+	description := task.Description
+	for _, tag := range task.Tags {
+		description += " +" + tag
+	}
+
+	return description
+}
+
+func treeIndent(depth int) string {
+	if depth == 0 {
+		return ""
+	}
+
+	return strings.Repeat("  ", depth-1) + "└ "
+}
+
+func progressLabel(progress tasks.Progress) string {
+	if progress.Total == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" [%d/%d]", progress.Done, progress.Total)
+}
+
+func blockedLabel(blocking []int) string {
+	if len(blocking) == 0 {
+		return ""
+	}
+
+	return " (blocked by " + tasks.JoinIDs(blocking, ", ") + ")"
+}
+
+// warnIfBlocked tells the user on stderr that a task was started even though
+// it still waits on other tasks.
+func warnIfBlocked(store tasks.Store, task tasks.Task) {
+	list, err := store.Load()
+	if err != nil {
+		return
+	}
+
+	if blocking := tasks.BlockedBy(workflow, list, task); len(blocking) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: task %d is blocked by unfinished tasks %s\n", task.ID, tasks.JoinIDs(blocking, ", "))
+	}
+}
+
+func printSearchResults(results []search.Result, matched int) {
+	switch output {
+	case outputJSON:
+		printJSON(results)
+		return
+	case outputJSONL:
+		for _, result := range results {
+			printJSON(result)
+		}
+		return
+	}
+
+	if matched == 0 {
+		fmt.Println("No matching tasks found.")
+		return
+	}
+
+	mark := highlighter()
+
+	fmt.Printf("%-4s %-12s %-16s %s\n", "ID", "Status", "Project", "Description")
+	for _, result := range results {
+		task := result.Task
+
+		var description, project []search.Match
+		tags := map[string][]search.Match{}
+		for _, match := range result.Matches {
+			switch match.Field {
+			case "description":
+				description = append(description, match)
+			case "tag":
+				tags[match.Tag] = append(tags[match.Tag], match)
+			case "project":
+				project = append(project, match)
+			}
+		}
+
+		text := mark(task.Description, description)
+		for _, tag := range task.Tags {
+			text += " +" + mark(tag, tags[tag])
+		}
+
+		projectText := "-"
+		if task.Project != "" {
+			projectText = mark(task.Project, project)
+		}
+
+		// Terminal highlighting adds invisible characters, so pad by hand.
+		padding := strings.Repeat(" ", max(0, 16-utf8.RuneCountInString(ansiCodes.Replace(projectText))))
+		fmt.Printf("%-4d %-12s %s%s %s\n", task.ID, task.Status, projectText, padding, text)
+	}
+
+	if len(results) < matched {
+		fmt.Printf("Showing %d of %d matching tasks.\n", len(results), matched)
+	}
+}
+
+var ansiCodes = strings.NewReplacer(boldStart, "", boldEnd, "")
+
+const (
+	boldStart = "\x1b[1m"
+	boldEnd   = "\x1b[0m"
+)
+
+// highlighter returns a function that marks the matched parts of a text:
+// bold on a terminal unless NO_COLOR is set, and in [brackets] otherwise.
+func highlighter() func(text string, matches []search.Match) string {
+	start, end := "[", "]"
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 && os.Getenv("NO_COLOR") == "" {
+		start, end = boldStart, boldEnd
+	}
+
+	return func(text string, matches []search.Match) string {
+		slices.SortFunc(matches, func(a, b search.Match) int { return cmp.Compare(a.Start, b.Start) })
+
+		var b strings.Builder
+		last := 0
+		for _, match := range matches {
+			if match.End <= last {
+				continue
+			}
+			b.WriteString(text[last:max(last, match.Start)])
+			b.WriteString(start + text[max(last, match.Start):match.End] + end)
+			last = match.End
+		}
+		b.WriteString(text[last:])
+
+		return b.String()
+	}
+}
+
+func printViews(views []viewEntry) {
+	switch output {
+	case outputJSON:
+		printJSON(views)
+		return
+	case outputJSONL:
+		for _, view := range views {
+			printJSON(view)
+		}
+		return
+	}
+
+	fmt.Printf("%-20s %-8s %s\n", "View", "Source", "Arguments")
+	for _, view := range views {
+		source := "saved"
+		if view.Builtin {
+			source = "built-in"
+		}
+		fmt.Printf("%-20s %-8s %s\n", view.Name, source, commandLine(view.Args))
+	}
+}
+
+func printViewResult(action string, view viewEntry) {
+	if output != outputText {
+		printJSON(viewResult{Action: action, View: view})
+		return
+	}
+
+	fmt.Printf("View %q %s\n", view.Name, action)
+}
+
+func printTagCounts(counts []tasks.TagCount) {
+	switch output {
+	case outputJSON:
+		printJSON(counts)
+		return
+	case outputJSONL:
+		for _, count := range counts {
+			printJSON(count)
+		}
+		return
+	}
+
+	if len(counts) == 0 {
+		fmt.Println("No tags found.")
+		return
+	}
+
+	fmt.Printf("%-20s %s\n", "Tag", "Tasks")
+	for _, count := range counts {
+		fmt.Printf("%-20s %d\n", "+"+count.Tag, count.Count)
+	}
+}
+
+func priorityLabel(priority tasks.Priority) string {
+	if priority == tasks.PriorityNone {
+		return "-"
+	}
+
+	return priority.String()
+}
+
+func projectLabel(project string) string {
+	if project == "" {
+		return "-"
+	}
+
+	return project
+}
+
+func overdueMarker(task tasks.Task, now time.Time) string {
+	if task.IsOverdue(workflow, now) {
+		return "!"
+	}
+
+	return ""
+}
+
+func timeLabel(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Format("2006-01-02 15:04")
+}
+
+// dueLabel hides the time of day for dates that were given without one,
+// which dateparse resolves to the last second of the day.
+func dueLabel(due time.Time) string {
+	if due.IsZero() {
+		return "-"
+	}
+
+	if due.Hour() == 23 && due.Minute() == 59 && due.Second() == 59 {
+		return due.Format("2006-01-02")
+	}
+
+	return due.Format("2006-01-02 15:04")
+}
+
+// printProjects shows one count column per workflow state.
+func printProjects(summaries []tasks.ProjectSummary, states []string) {
+	switch output {
+	case outputJSON:
+		printJSON(summaries)
+		return
+	case outputJSONL:
+		for _, summary := range summaries {
+			printJSON(summary)
+		}
+		return
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("No projects found.")
+		return
+	}
+
+	fmt.Printf("%-24s", "Project")
+	for _, state := range states {
+		fmt.Printf(" %*s", columnWidth(state), projectColumnTitle(state))
+	}
+	fmt.Printf(" %6s\n", "Total")
+
+	for _, summary := range summaries {
+		fmt.Printf("%-24s", summary.Name)
+		for _, state := range states {
+			fmt.Printf(" %*d", columnWidth(state), summary.Counts[state])
+		}
+		fmt.Printf(" %6d\n", summary.Total)
+	}
+}
+
+func projectColumnTitle(state string) string {
+	first, size := utf8.DecodeRuneInString(state)
+	return string(unicode.ToUpper(first)) + state[size:]
+}
+
+func columnWidth(state string) int {
+	return max(6, utf8.RuneCountInString(state))
+}
+
+func printHistory(task tasks.Task) {
+	cycleTime, finished := tasks.CycleTime(workflow, task)
+
+	if output != outputText {
+		result := historyResult{TaskID: task.ID, Events: task.History}
+		if result.Events == nil {
+			result.Events = []tasks.Event{}
+		}
+		if finished {
+			seconds := cycleTime.Seconds()
+			result.CycleTimeSeconds = &seconds
+		}
+		printJSON(result)
+		return
+	}
+
+	if len(task.History) == 0 {
+		fmt.Printf("No history recorded for task %d.\n", task.ID)
+		return
+	}
+
+	fmt.Printf("Task %d: %s\n\n", task.ID, task.Description)
+	fmt.Printf("%-17s %s\n", "When", "Change")
+	for _, event := range task.History {
+		fmt.Printf("%-17s %s\n", event.At.Format("2006-01-02 15:04"), describeEvent(event))
+	}
+
+	if finished {
+		fmt.Printf("\nCycle time: %s\n", formatDuration(cycleTime))
+	}
+}
+
+func describeEvent(event tasks.Event) string {
+	switch {
+	case event.Field == "status" && event.Old == "":
+		return "created as " + event.New
+	case event.Field == "status":
+		return fmt.Sprintf("status %s -> %s", event.Old, event.New)
+	default:
+		return fmt.Sprintf("%s %q -> %q", event.Field, event.Old, event.New)
+	}
+}
+
+// formatDuration rounds to the two largest units, e.g. "2d 3h" or "45m".
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func printJournalResult(action string, entry tasks.JournalEntry) {
+	if output != outputText {
+		printJSON(journalResult{Action: action, Command: entry.Command, At: entry.At, Changes: entry.Changes})
+		return
+	}
+
+	verb := "Undid"
+	if action == "redone" {
+		verb = "Redid"
+	}
+
+	fmt.Printf("%s: %s (%s)\n", verb, entry.Command, countTasks(len(entry.Changes)))
+}
+
+// printJournal lists undo or redo entries, the next one to apply first.
+func printJournal(entries []tasks.JournalEntry, kind string) {
+	switch output {
+	case outputJSON:
+		printJSON(entries)
+		return
+	case outputJSONL:
+		for _, entry := range entries {
+			printJSON(entry)
+		}
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("Nothing to %s.\n", kind)
+		return
+	}
+
+	fmt.Printf("%-3s %-17s %-8s %s\n", "#", "When", "Tasks", "Command")
+	for i, entry := range entries {
+		fmt.Printf("%-3d %-17s %-8d %s\n", i+1, entry.At.Format("2006-01-02 15:04"), len(entry.Changes), entry.Command)
+	}
+}
+
+// splitSubtree separates the tasks that went with affected[0] into its
+// subtasks and its parents.
+func splitSubtree(affected []tasks.Task) (subtasks, parents []tasks.Task) {
+	byID := map[int]tasks.Task{}
+	for _, other := range affected[1:] {
+		byID[other.ID] = other
+	}
+
+	isParent := map[int]bool{}
+	for ID := affected[0].ParentID; byID[ID].ID != 0 && !isParent[ID]; ID = byID[ID].ParentID {
+		isParent[ID] = true
+	}
+
+	for _, other := range affected[1:] {
+		if isParent[other.ID] {
+			parents = append(parents, other)
+		} else {
+			subtasks = append(subtasks, other)
+		}
+	}
+
+	return subtasks, parents
+}
+
+func taskIDs(list []tasks.Task) []int {
+	IDs := make([]int, len(list))
+	for i, task := range list {
+		IDs[i] = task.ID
+	}
+
+	return IDs
+}
+
+// countOf is "1 subtask" or "n subtasks".
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func countTasks(n int) string {
+	if n == 1 {
+		return "1 task changed"
+	}
+
+	return fmt.Sprintf("%d tasks changed", n)
+}
+
+func printLocation(location config.Location) {
+	if output != outputText {
+		printJSON(locationResult{Path: location.Path, Source: string(location.Source), Reason: location.Reason()})
+		return
+	}
+
+	fmt.Printf("%s (%s)\n", location.Path, location.Reason())
+}
+
+// printJSON writes v indented for --output json and as a single line for
+// --output jsonl, so every jsonl line is one self-contained document.
+func printJSON(v any) {
+	var data []byte
+	var err error
+
+	if output == outputJSONL {
+		data, err = json.Marshal(v)
+	} else {
+		data, err = json.MarshalIndent(v, "", "  ")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding output: %v\n", err)
+		os.Exit(exitFailure)
+	}
+
+	fmt.Println(string(data))
+}
+
+var errorCodes = map[int]string{
+	exitFailure:  "error",
+	exitUsage:    "usage",
+	exitNotFound: "not_found",
+	exitInvalid:  "invalid",
+	exitCorrupt:  "corrupt_store",
+	exitLocked:   "locked",
+	exitConflict: "conflict",
+}
+
+func printError(message string, code int) {
+	data, _ := json.Marshal(errorResult{Error: errorDetail{Code: errorCodes[code], Message: message, ExitCode: code}})
+	fmt.Fprintln(os.Stderr, string(data))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"slices"
+	"testing"
+)
+
+func TestSplitSubtree(t *testing.T) {
+	affected := []tasks.Task{
+		{ID: 3, ParentID: 2},
+		{ID: 4, ParentID: 3},
+		{ID: 5, ParentID: 4},
+		{ID: 2, ParentID: 1},
+		{ID: 1},
+	}
+
+	subtasks, parents := splitSubtree(affected)
+
+	if got := taskIDs(subtasks); !slices.Equal(got, []int{4, 5}) {
+		t.Errorf("Expected subtasks [4 5], got %v", got)
+	}
+	if got := taskIDs(parents); !slices.Equal(got, []int{2, 1}) {
+		t.Errorf("Expected parents [2 1], got %v", got)
+	}
+}
+
+func TestCountOf(t *testing.T) {
+	cases := map[int]string{0: "0 subtasks", 1: "1 subtask", 2: "2 subtasks"}
+
+	for n, want := range cases {
+		if got := countOf(n, "subtask"); got != want {
+			t.Errorf("countOf(%d): expected %q, got %q", n, want, got)
+		}
+	}
+}
+
+func TestProjectColumnTitle(t *testing.T) {
+	cases := map[string]string{"todo": "Todo", "in progress": "In progress", "überprüfung": "Überprüfung"}
+
+	for state, want := range cases {
+		if got := projectColumnTitle(state); got != want {
+			t.Errorf("projectColumnTitle(%q): expected %q, got %q", state, want, got)
+		}
+	}
+
+	if got := columnWidth("überprüfung"); got != 11 {
+		t.Errorf("Expected width 11, got %d", got)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// builtinViews can be used with every task file. A saved view of the same
+// name takes the place of a built-in one. They follow the workflow: today
+// leaves out every closed status, and in-progress-by-age only exists when
+// there is an "in progress" state.
+func builtinViews() map[string]tasks.View {
+	var today []string
+	for _, state := range workflow.States {
+		if workflow.IsClosed(state) {
+			today = append(today, statusTerm("!=", state))
+		}
+	}
+	today = append(today, "due<=today")
+
+	views := map[string]tasks.View{
+		"today":   {Args: []string{strings.Join(today, " "), "--sort", "due,-priority"}},
+		"overdue": {Args: []string{"overdue", "--sort", "due"}},
+	}
+	if workflow.HasState("in progress") {
+		views["in-progress-by-age"] = tasks.View{Args: []string{
+			statusTerm("=", "in progress"), "--sort", "created",
+			"--columns", "id,overdue,priority,due,created,updated,project,description",
+		}}
+	}
+
+	return views
+}
+
+// statusTerm compares the status with a query term, quoting statuses that
+// contain spaces.
+func statusTerm(op, status string) string {
+	if strings.ContainsAny(status, " \t") {
+		status = `"` + status + `"`
+	}
+
+	return "status" + op + status
+}
+
+// viewCommands cannot be used as view names.
+var viewCommands = []string{"list", "save", "delete"}
+
+func handleView(store tasks.Store, args []string) {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) > 1 {
+			exitUsageError(fmt.Sprintf("Error: unexpected argument %q.", args[1]))
+		}
+
+		meta, err := store.LoadMeta()
+		if err != nil {
+			exitFatalError("Error listing views", err)
+		}
+
+		printViews(allViews(meta.Views))
+	case "save":
+		if len(args) < 2 {
+			exitUsageError("Error: missing view name.")
+		} else if len(args) < 3 {
+			exitUsageError("Error: missing list arguments to save.")
+		}
+
+		const failure = "Error saving view"
+		name := parseViewName(args[1], failure)
+
+		// Parse the arguments now, so a view that list would reject is
+		// never saved.
+		view := tasks.View{Args: args[2:]}
+		parseListArgs(failure, view.Args)
+
+		replaced, err := tasks.SaveView(store, name, view)
+		if err != nil {
+			exitFatalError(failure, err)
+		}
+
+		action := "saved"
+		if replaced {
+			action = "updated"
+		}
+		printViewResult(action, viewEntry{Name: name, Args: view.Args})
+	case "delete":
+		if len(args) < 2 {
+			exitUsageError("Error: missing view name.")
+		} else if len(args) > 2 {
+			exitUsageError(fmt.Sprintf("Error: unexpected argument %q.", args[2]))
+		}
+
+		const failure = "Error deleting view"
+		name := parseViewName(args[1], failure)
+
+		meta, err := store.LoadMeta()
+		if err != nil {
+			exitFatalError(failure, err)
+		}
+
+		view, saved := meta.Views[name]
+		if _, builtin := builtinViews()[name]; builtin && !saved {
+			exitFatalError(failure, &tasks.ValidationError{Field: "view", Message: fmt.Sprintf("%q is a built-in view and cannot be deleted", name)})
+		}
+
+		if err := tasks.DeleteView(store, name); err != nil {
+			exitFatalError(failure, err)
+		}
+
+		printViewResult("deleted", viewEntry{Name: name, Args: view.Args})
+	default:
+		const failure = "Error showing view"
+		name := parseViewName(args[0], failure)
+
+		meta, err := store.LoadMeta()
+		if err != nil {
+			exitFatalError(failure, err)
+		}
+
+		view, ok := meta.Views[name]
+		if !ok {
+			view, ok = builtinViews()[name]
+		}
+		if !ok {
+			exitFatalError(failure, &tasks.ValidationError{Field: "view", Message: fmt.Sprintf("unknown view %q (see task-cli view list)", name)})
+		}
+
+		runList(store, parseListArgs(failure, view.Args, args[1:]))
+	}
+}
+
+func parseViewName(value, failure string) string {
+	name, err := tasks.NormalizeViewName(value)
+	if err == nil && slices.Contains(viewCommands, name) {
+		err = &tasks.ValidationError{Field: "view", Message: fmt.Sprintf("%q cannot be used as a view name", name)}
+	}
+	if err != nil {
+		exitFatalError(failure, err)
+	}
+
+	return name
+}
+
+// allViews merges the saved views with the built-in ones they do not
+// replace, sorted by name.
+func allViews(saved map[string]tasks.View) []viewEntry {
+	var entries []viewEntry
+	for name, view := range builtinViews() {
+		if _, ok := saved[name]; !ok {
+			entries = append(entries, viewEntry{Name: name, Args: view.Args, Builtin: true})
+		}
+	}
+	for name, view := range saved {
+		entries = append(entries, viewEntry{Name: name, Args: view.Args})
+	}
+
+	slices.SortFunc(entries, func(a, b viewEntry) int { return strings.Compare(a.Name, b.Name) })
+	return entries
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	ConfigEnv      = "TASK_CLI_CONFIG"
+	configFileName = "config.json"
+
+	// DefaultConfirmAbove is how many tasks a bulk command may change
+	// without asking first.
+	DefaultConfirmAbove = 10
+)
+
+// Config holds user settings read from config.json. Every field is optional.
+type Config struct {
+	Workflow tasks.Workflow `json:"workflow"`
+	// UIDs gives new tasks a globally unique ID next to the numeric one.
+	UIDs bool `json:"uids"`
+	// ConfirmAbove is how many tasks a bulk command may change before it
+	// asks for confirmation.
+	ConfirmAbove int `json:"confirm_above"`
+}
+
+func Default() Config {
+	return Config{Workflow: tasks.DefaultWorkflow(), ConfirmAbove: DefaultConfirmAbove}
+}
+
+// ConfigPath returns TASK_CLI_CONFIG if set, otherwise
+// $XDG_CONFIG_HOME/task-cli/config.json.
+func ConfigPath(getenv func(string) string) (string, error) {
+	if value := getenv(ConfigEnv); value != "" {
+		return value, nil
+	}
+
+	dir, err := configDir(getenv)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, appDirName, configFileName), nil
+}
+
+// Load reads the config file at ConfigPath. Without TASK_CLI_CONFIG or a
+// config directory there is no file to read, which yields the defaults.
+func Load(getenv func(string) string) (Config, error) {
+	if getenv(ConfigEnv) == "" {
+		if _, err := configDir(getenv); err != nil {
+			return Default(), nil
+		}
+	}
+
+	path, err := ConfigPath(getenv)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return LoadConfig(path)
+}
+
+// LoadConfig reads the config file at path. A missing file yields the
+// defaults.
+func LoadConfig(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	var file Config
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if file.Workflow.States != nil || file.Workflow.Transitions != nil || file.Workflow.Initial != "" || file.Workflow.Closed != nil {
+		if err := file.Workflow.Validate(); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+		cfg.Workflow = file.Workflow
+	}
+	cfg.UIDs = file.UIDs
+
+	if file.ConfirmAbove < 0 {
+		return Config{}, fmt.Errorf("%s: %w", path, &tasks.ValidationError{Field: "confirm_above", Message: "confirm_above cannot be negative"})
+	}
+	if file.ConfirmAbove > 0 {
+		cfg.ConfirmAbove = file.ConfirmAbove
+	}
+
+	return cfg, nil
+}
+
+func configDir(getenv func(string) string) (string, error) {
+	if dir := getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	if runtime.GOOS == "windows" {
+		if dir := getenv("APPDATA"); dir != "" {
+			return dir, nil
+		}
+	}
+
+	home := getenv("HOME")
+	if home == "" {
+		return "", errors.New("cannot determine config directory: neither XDG_CONFIG_HOME nor HOME is set")
+	}
+
+	return filepath.Join(home, ".config"), nil
+}
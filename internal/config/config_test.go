@@ -0,0 +1,154 @@
+package config
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	return path
+}
+
+func TestConfigPath(t *testing.T) {
+	t.Run("Environment variable wins", func(t *testing.T) {
+		path, err := ConfigPath(fakeEnv(map[string]string{ConfigEnv: "/etc/task-cli.json", "XDG_CONFIG_HOME": "/xdg"}))
+		if err != nil {
+			t.Fatalf("ConfigPath returned error: %v", err)
+		}
+		if path != "/etc/task-cli.json" {
+			t.Errorf("Expected /etc/task-cli.json, got %s", path)
+		}
+	})
+
+	t.Run("Falls back to the config directory", func(t *testing.T) {
+		path, err := ConfigPath(fakeEnv(map[string]string{"HOME": "/home/me"}))
+		if err != nil {
+			t.Fatalf("ConfigPath returned error: %v", err)
+		}
+		if want := filepath.Join("/home/me", ".config", "task-cli", "config.json"); path != want {
+			t.Errorf("Expected %s, got %s", want, path)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Yields defaults without a config directory", func(t *testing.T) {
+		cfg, err := Load(fakeEnv(map[string]string{}))
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+		if !slices.Equal(cfg.Workflow.States, tasks.DefaultWorkflow().States) || cfg.ConfirmAbove != DefaultConfirmAbove {
+			t.Errorf("Expected the defaults, got %+v", cfg)
+		}
+	})
+
+	t.Run("Reads the file named by the environment", func(t *testing.T) {
+		path := writeConfig(t, `{"uids": true}`)
+
+		cfg, err := Load(fakeEnv(map[string]string{ConfigEnv: path}))
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+		if !cfg.UIDs {
+			t.Errorf("Expected uids to be read, got %+v", cfg)
+		}
+	})
+
+	t.Run("Reports an invalid config file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "task-cli", "config.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll returned error: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(`{"workflow": `), 0o644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+
+		if _, err := Load(fakeEnv(map[string]string{"XDG_CONFIG_HOME": dir})); err == nil {
+			t.Error("Expected an error for malformed JSON")
+		}
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("Missing file yields defaults", func(t *testing.T) {
+		cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+		if err != nil {
+			t.Fatalf("LoadConfig returned error: %v", err)
+		}
+		if !slices.Equal(cfg.Workflow.States, tasks.DefaultWorkflow().States) {
+			t.Errorf("Expected default states, got %v", cfg.Workflow.States)
+		}
+	})
+
+	t.Run("Reads a custom workflow", func(t *testing.T) {
+		path := writeConfig(t, `{"workflow": {
+			"states": ["todo", "in progress", "review", "done"],
+			"transitions": {"todo": ["in progress"], "in progress": ["review"], "review": ["done", "in progress"]}
+		}}`)
+
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig returned error: %v", err)
+		}
+
+		if cfg.Workflow.Initial != "todo" {
+			t.Errorf("Expected initial state todo, got %q", cfg.Workflow.Initial)
+		}
+		if !cfg.Workflow.CanTransition("review", "done") || cfg.Workflow.CanTransition("todo", "done") {
+			t.Errorf("Transitions not applied: %+v", cfg.Workflow.Transitions)
+		}
+	})
+
+	t.Run("Reads the uids option", func(t *testing.T) {
+		cfg, err := LoadConfig(writeConfig(t, `{"uids": true}`))
+		if err != nil {
+			t.Fatalf("LoadConfig returned error: %v", err)
+		}
+		if !cfg.UIDs || !slices.Equal(cfg.Workflow.States, tasks.DefaultWorkflow().States) {
+			t.Errorf("Expected uids with the default workflow, got %+v", cfg)
+		}
+		if cfg.ConfirmAbove != DefaultConfirmAbove {
+			t.Errorf("Expected default confirm_above %d, got %d", DefaultConfirmAbove, cfg.ConfirmAbove)
+		}
+	})
+
+	t.Run("Reads confirm_above", func(t *testing.T) {
+		cfg, err := LoadConfig(writeConfig(t, `{"confirm_above": 50}`))
+		if err != nil {
+			t.Fatalf("LoadConfig returned error: %v", err)
+		}
+		if cfg.ConfirmAbove != 50 {
+			t.Errorf("Expected confirm_above 50, got %d", cfg.ConfirmAbove)
+		}
+
+		if _, err := LoadConfig(writeConfig(t, `{"confirm_above": -1}`)); !errors.Is(err, tasks.ErrValidation) {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("Rejects an invalid workflow", func(t *testing.T) {
+		path := writeConfig(t, `{"workflow": {"states": ["open", "closed"]}}`)
+
+		if _, err := LoadConfig(path); !errors.Is(err, tasks.ErrValidation) {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("Rejects malformed JSON", func(t *testing.T) {
+		if _, err := LoadConfig(writeConfig(t, `{"workflow": `)); err == nil {
+			t.Error("Expected error for malformed config")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	FileEnv         = "TASK_CLI_FILE"
+	ProjectFileName = ".tasks.json"
+	ProjectDBName   = ".tasks.db"
+	appDirName      = "task-cli"
+	dataFileName    = "tasks.json"
+	dataDBName      = "tasks.db"
+)
+
+type Source string
+
+const (
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
+	SourceProject Source = "project"
+	SourceDefault Source = "default"
+)
+
+type Location struct {
+	Path   string
+	Source Source
+}
+
+func (l Location) Reason() string {
+	switch l.Source {
+	case SourceFlag:
+		return "set by --file flag"
+	case SourceEnv:
+		return "set by " + FileEnv + " environment variable"
+	case SourceProject:
+		return "found " + filepath.Base(l.Path) + " in " + filepath.Dir(l.Path)
+	default:
+		return "default data directory"
+	}
+}
+
+// ResolveTasksFile picks the task file in order of precedence: the --file
+// flag, TASK_CLI_FILE, a .tasks.json in cwd or one of its parents, and
+// finally $XDG_DATA_HOME/task-cli/tasks.json. In the last two cases an
+// SQLite database next to the JSON file (.tasks.db, tasks.db) wins.
+func ResolveTasksFile(flagValue string, getenv func(string) string, cwd string) (Location, error) {
+	if flagValue != "" {
+		return Location{Path: flagValue, Source: SourceFlag}, nil
+	}
+
+	if value := getenv(FileEnv); value != "" {
+		return Location{Path: value, Source: SourceEnv}, nil
+	}
+
+	if path, ok := findProjectFile(cwd); ok {
+		return Location{Path: path, Source: SourceProject}, nil
+	}
+
+	dir, err := dataDir(getenv)
+	if err != nil {
+		return Location{}, err
+	}
+
+	dir = filepath.Join(dir, appDirName)
+	if path := filepath.Join(dir, dataDBName); isFile(path) {
+		return Location{Path: path, Source: SourceDefault}, nil
+	}
+
+	return Location{Path: filepath.Join(dir, dataFileName), Source: SourceDefault}, nil
+}
+
+func IsSQLite(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return true
+	default:
+		return false
+	}
+}
+
+// SiblingPath returns path with its extension swapped for the given backend,
+// e.g. tasks.json -> tasks.db.
+func SiblingPath(path string, sqlite bool) string {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	if sqlite {
+		return base + ".db"
+	}
+
+	return base + ".json"
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func findProjectFile(dir string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+
+	for {
+		for _, name := range []string{ProjectDBName, ProjectFileName} {
+			if path := filepath.Join(dir, name); isFile(path) {
+				return path, true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func dataDir(getenv func(string) string) (string, error) {
+	if dir := getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	if runtime.GOOS == "windows" {
+		if dir := getenv("LOCALAPPDATA"); dir != "" {
+			return dir, nil
+		}
+	}
+
+	home := getenv("HOME")
+	if home == "" {
+		return "", errors.New("cannot determine data directory: neither XDG_DATA_HOME nor HOME is set")
+	}
+
+	return filepath.Join(home, ".local", "share"), nil
+}
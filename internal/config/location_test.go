@@ -0,0 +1,157 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func fakeEnv(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
+
+func TestResolveTasksFile(t *testing.T) {
+	t.Run("Flag takes precedence over everything else", func(t *testing.T) {
+		env := fakeEnv(map[string]string{FileEnv: "/env/tasks.json", "HOME": "/home/me"})
+
+		loc, err := ResolveTasksFile("/flag/tasks.json", env, t.TempDir())
+		if err != nil {
+			t.Fatalf("ResolveTasksFile returned error: %v", err)
+		}
+
+		if loc.Path != "/flag/tasks.json" || loc.Source != SourceFlag {
+			t.Fatalf("unexpected location: %+v", loc)
+		}
+	})
+
+	t.Run("Environment variable is used when no flag is given", func(t *testing.T) {
+		env := fakeEnv(map[string]string{FileEnv: "/env/tasks.json", "HOME": "/home/me"})
+
+		loc, err := ResolveTasksFile("", env, t.TempDir())
+		if err != nil {
+			t.Fatalf("ResolveTasksFile returned error: %v", err)
+		}
+
+		if loc.Path != "/env/tasks.json" || loc.Source != SourceEnv {
+			t.Fatalf("unexpected location: %+v", loc)
+		}
+	})
+
+	t.Run("Project file is found in a parent directory", func(t *testing.T) {
+		root := t.TempDir()
+		projectFile := filepath.Join(root, ProjectFileName)
+		if err := os.WriteFile(projectFile, nil, 0o644); err != nil {
+			t.Fatalf("Failed to write project file: %v", err)
+		}
+
+		cwd := filepath.Join(root, "src", "pkg")
+		if err := os.MkdirAll(cwd, 0o755); err != nil {
+			t.Fatalf("Failed to create dirs: %v", err)
+		}
+
+		loc, err := ResolveTasksFile("", fakeEnv(map[string]string{"HOME": "/home/me"}), cwd)
+		if err != nil {
+			t.Fatalf("ResolveTasksFile returned error: %v", err)
+		}
+
+		if loc.Path != projectFile || loc.Source != SourceProject {
+			t.Fatalf("unexpected location: %+v", loc)
+		}
+	})
+
+	t.Run("Falls back to XDG data directory", func(t *testing.T) {
+		env := fakeEnv(map[string]string{"XDG_DATA_HOME": "/data", "HOME": "/home/me"})
+
+		loc, err := ResolveTasksFile("", env, t.TempDir())
+		if err != nil {
+			t.Fatalf("ResolveTasksFile returned error: %v", err)
+		}
+
+		want := filepath.Join("/data", "task-cli", "tasks.json")
+		if loc.Path != want || loc.Source != SourceDefault {
+			t.Fatalf("Expected %q from default, got %+v", want, loc)
+		}
+	})
+
+	t.Run("Falls back to ~/.local/share without XDG_DATA_HOME", func(t *testing.T) {
+		env := fakeEnv(map[string]string{"HOME": "/home/me"})
+
+		loc, err := ResolveTasksFile("", env, t.TempDir())
+		if err != nil {
+			t.Fatalf("ResolveTasksFile returned error: %v", err)
+		}
+
+		want := filepath.Join("/home/me", ".local", "share", "task-cli", "tasks.json")
+		if loc.Path != want {
+			t.Fatalf("Expected %q, got %q", want, loc.Path)
+		}
+	})
+
+	t.Run("Returns error when no data directory can be determined", func(t *testing.T) {
+		_, err := ResolveTasksFile("", fakeEnv(nil), t.TempDir())
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
+func TestResolveTasksFileSQLite(t *testing.T) {
+	t.Run("Project database wins over project JSON file", func(t *testing.T) {
+		root := t.TempDir()
+		for _, name := range []string{ProjectFileName, ProjectDBName} {
+			if err := os.WriteFile(filepath.Join(root, name), nil, 0o644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+
+		loc, err := ResolveTasksFile("", fakeEnv(map[string]string{"HOME": "/home/me"}), root)
+		if err != nil {
+			t.Fatalf("ResolveTasksFile returned error: %v", err)
+		}
+
+		if loc.Path != filepath.Join(root, ProjectDBName) {
+			t.Fatalf("Expected project database, got %+v", loc)
+		}
+	})
+
+	t.Run("Default database wins over default JSON file", func(t *testing.T) {
+		data := t.TempDir()
+		dbPath := filepath.Join(data, "task-cli", "tasks.db")
+		if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+			t.Fatalf("Failed to create dirs: %v", err)
+		}
+		if err := os.WriteFile(dbPath, nil, 0o644); err != nil {
+			t.Fatalf("Failed to write database: %v", err)
+		}
+
+		loc, err := ResolveTasksFile("", fakeEnv(map[string]string{"XDG_DATA_HOME": data}), t.TempDir())
+		if err != nil {
+			t.Fatalf("ResolveTasksFile returned error: %v", err)
+		}
+
+		if loc.Path != dbPath || loc.Source != SourceDefault {
+			t.Fatalf("Expected %q from default, got %+v", dbPath, loc)
+		}
+	})
+}
+
+func TestSiblingPath(t *testing.T) {
+	cases := []struct {
+		path   string
+		sqlite bool
+		want   string
+	}{
+		{"tasks.json", true, "tasks.db"},
+		{"/a/.tasks.json", true, "/a/.tasks.db"},
+		{"/a/tasks.db", false, "/a/tasks.json"},
+		{"tasks", true, "tasks.db"},
+	}
+
+	for _, c := range cases {
+		if got := SiblingPath(c.path, c.sqlite); got != c.want {
+			t.Errorf("SiblingPath(%q, %v): got %q, want %q", c.path, c.sqlite, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package dateparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sun":       time.Sunday,
+	"mon":       time.Monday,
+	"tue":       time.Tuesday,
+	"wed":       time.Wednesday,
+	"thu":       time.Thursday,
+	"fri":       time.Friday,
+	"sat":       time.Saturday,
+}
+
+var absoluteLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Parse turns an absolute date ("2026-03-01", "2026-03-01 17:00") or a
+// relative phrase ("today", "tomorrow", "friday", "next friday",
+// "in 3 days", "in 2 weeks") into a time in now's location. Dates without
+// a time of day resolve to the end of that day, so a task due "today" is
+// not overdue until midnight.
+func Parse(value string, now time.Time) (time.Time, error) {
+	trimmed := strings.Join(strings.Fields(value), " ")
+	if trimmed == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, now.Location()); err == nil {
+			if layout == "2006-01-02" {
+				return endOfDay(t), nil
+			}
+			return t, nil
+		}
+	}
+
+	input := strings.ToLower(trimmed)
+	today := startOfDay(now)
+
+	switch input {
+	case "today", "tonight", "eod":
+		return endOfDay(today), nil
+	case "tomorrow":
+		return endOfDay(today.AddDate(0, 0, 1)), nil
+	case "yesterday":
+		return endOfDay(today.AddDate(0, 0, -1)), nil
+	case "next week":
+		return endOfDay(today.AddDate(0, 0, 7)), nil
+	case "next month":
+		return endOfDay(today.AddDate(0, 1, 0)), nil
+	}
+
+	if day, ok := weekdays[input]; ok {
+		return endOfDay(upcomingWeekday(today, day)), nil
+	}
+
+	if rest, ok := strings.CutPrefix(input, "next "); ok {
+		if day, ok := weekdays[rest]; ok {
+			return endOfDay(weekdayOfNextWeek(today, day)), nil
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(input, "in "); ok {
+		if t, ok := parseOffset(rest, now); ok {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date %q (try 2026-01-31, tomorrow, next friday or in 3 days)", value)
+}
+
+// upcomingWeekday returns the first given weekday strictly after today.
+func upcomingWeekday(today time.Time, day time.Weekday) time.Time {
+	days := (int(day) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+
+	return today.AddDate(0, 0, days)
+}
+
+// weekdayOfNextWeek returns the given weekday in the calendar week (Monday
+// to Sunday) after the one containing today, which is what people usually
+// mean by "next friday".
+func weekdayOfNextWeek(today time.Time, day time.Weekday) time.Time {
+	nextMonday := today.AddDate(0, 0, 8-isoWeekday(today.Weekday()))
+	return nextMonday.AddDate(0, 0, isoWeekday(day)-1)
+}
+
+func isoWeekday(day time.Weekday) int {
+	if day == time.Sunday {
+		return 7
+	}
+
+	return int(day)
+}
+
+func parseOffset(value string, now time.Time) (time.Time, bool) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || n < 0 {
+		if fields[0] != "a" && fields[0] != "an" {
+			return time.Time{}, false
+		}
+		n = 1
+	}
+
+	today := startOfDay(now)
+
+	switch strings.TrimSuffix(fields[1], "s") {
+	case "minute", "min":
+		return now.Add(time.Duration(n) * time.Minute), true
+	case "hour":
+		return now.Add(time.Duration(n) * time.Hour), true
+	case "day":
+		return endOfDay(today.AddDate(0, 0, n)), true
+	case "week":
+		return endOfDay(today.AddDate(0, 0, 7*n)), true
+	case "month":
+		return endOfDay(today.AddDate(0, n, 0)), true
+	case "year":
+		return endOfDay(today.AddDate(n, 0, 0)), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func endOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 23, 59, 59, 0, t.Location())
+}
+
+// ParseDuration accepts Go durations such as "36h" or "90m" as well as whole
+// days and weeks: "30d", "2w".
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				break
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (try 30d, 2w or 12h)", value)
+	}
+
+	return d, nil
+}
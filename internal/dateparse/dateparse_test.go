@@ -0,0 +1,97 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 23, 59, 59, 0, time.UTC)
+	}
+
+	cases := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2026-03-10", day(2026, 3, 10)},
+		{"2026-03-10 17:00", time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)},
+		{"2026-03-10T17:00:00Z", time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)},
+		{"today", day(2026, 3, 4)},
+		{"Tomorrow", day(2026, 3, 5)},
+		{"yesterday", day(2026, 3, 3)},
+		{"friday", day(2026, 3, 6)},
+		{"wed", day(2026, 3, 11)},
+		{"next friday", day(2026, 3, 13)},
+		{"next  monday", day(2026, 3, 9)},
+		{"next week", day(2026, 3, 11)},
+		{"in 3 days", day(2026, 3, 7)},
+		{"in 1 day", day(2026, 3, 5)},
+		{"in 2 weeks", day(2026, 3, 18)},
+		{"in a month", day(2026, 4, 4)},
+		{"in 2 hours", time.Date(2026, 3, 4, 12, 30, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		got, err := Parse(c.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", c.input, err)
+			continue
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("Parse(%q): got %v, want %v", c.input, got, c.want)
+		}
+	}
+}
+
+func TestParseNextWeekdayOnSunday(t *testing.T) {
+	sunday := time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)
+
+	got, err := Parse("next friday", sunday)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	want := time.Date(2026, 3, 13, 23, 59, 59, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+
+	for _, input := range []string{"", "someday", "in x days", "next blursday", "2026-13-01", "in 3 fortnights"} {
+		if _, err := Parse(input, now); err == nil {
+			t.Errorf("Parse(%q): expected error, got nil", input)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"30d":   30 * 24 * time.Hour,
+		"2w":    14 * 24 * time.Hour,
+		"12h":   12 * time.Hour,
+		"1h30m": 90 * time.Minute,
+		"0d":    0,
+	} {
+		got, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseDuration(%q): got %v, want %v", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "d", "-3d", "3 days", "soon", "-1h"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q): expected error, got nil", input)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package query
+
+import (
+	"TaskTrackerCLI/internal/dateparse"
+	"TaskTrackerCLI/internal/tasks"
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldFunc builds the predicate for a comparison "field op value".
+type fieldFunc func(op, value string, opts Options) (predicate, error)
+
+// operatorError reports an operator that makes no sense for a field, such
+// as tag>x.
+type operatorError struct {
+	field string
+}
+
+func (e operatorError) Error() string {
+	return "unsupported operator for " + e.field
+}
+
+// fieldOrder lists the field names for error messages.
+var fieldOrder = []string{"id", "status", "priority", "project", "tag", "description", "created", "updated", "due", "parent"}
+
+var fields = map[string]fieldFunc{
+	"id":          idField,
+	"status":      statusField,
+	"priority":    priorityField,
+	"project":     projectField,
+	"tag":         tagField,
+	"tags":        tagField,
+	"description": descriptionField,
+	"desc":        descriptionField,
+	"created":     dateField("created", func(task tasks.Task) time.Time { return task.CreatedAt }),
+	"updated":     dateField("updated", func(task tasks.Task) time.Time { return task.UpdatedAt }),
+	"due":         dateField("due", func(task tasks.Task) time.Time { return task.DueAt }),
+	"parent":      parentField,
+}
+
+func fieldNames() string {
+	return strings.Join(fieldOrder, ", ")
+}
+
+// keywords are the terms that stand on their own.
+var keywords = map[string]func(opts Options) predicate{
+	"overdue": func(opts Options) predicate {
+		return func(task tasks.Task, _ []tasks.Task) bool {
+			return task.IsOverdue(opts.Workflow, opts.Now)
+		}
+	},
+	"ready": func(opts Options) predicate {
+		return func(task tasks.Task, all []tasks.Task) bool {
+			return !opts.Workflow.IsClosed(task.Status) && len(tasks.BlockedBy(opts.Workflow, all, task)) == 0
+		}
+	},
+	"blocked": func(opts Options) predicate {
+		return func(task tasks.Task, all []tasks.Task) bool {
+			return len(tasks.BlockedBy(opts.Workflow, all, task)) > 0
+		}
+	},
+}
+
+// holds applies an operator to the result of comparing a field with the
+// query value (negative, zero or positive, as from cmp.Compare).
+func holds(op string, c int) bool {
+	switch op {
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	default:
+		return c == 0
+	}
+}
+
+func isOrdering(op string) bool {
+	return op == "<" || op == "<=" || op == ">" || op == ">="
+}
+
+func isContains(op string) bool {
+	return op == "~" || op == "!~"
+}
+
+// textMatcher matches a substring or a regular expression written as /re/,
+// both ignoring case.
+func textMatcher(value string) (func(string) bool, error) {
+	if len(value) > 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+		re, err := regexp.Compile("(?i)" + value[1:len(value)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+		return re.MatchString, nil
+	}
+
+	lower := strings.ToLower(value)
+	return func(s string) bool { return strings.Contains(strings.ToLower(s), lower) }, nil
+}
+
+// textField handles the operators shared by the string fields: "~" and
+// "!~" match, the others compare with equal.
+func textField(name, op, value string, get func(tasks.Task) string, equal func(a, b string) bool) (predicate, error) {
+	if isOrdering(op) {
+		return nil, operatorError{name}
+	}
+
+	if isContains(op) {
+		matches, err := textMatcher(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(task tasks.Task, _ []tasks.Task) bool {
+			return matches(get(task)) == (op == "~")
+		}, nil
+	}
+
+	return func(task tasks.Task, _ []tasks.Task) bool {
+		return equal(get(task), value) == (op != "!=")
+	}, nil
+}
+
+func statusField(op, value string, opts Options) (predicate, error) {
+	if !isContains(op) && opts.Statuses != nil && !slices.Contains(opts.Statuses, value) {
+		return nil, fmt.Errorf("unknown status %q (allowed: %s)", value, strings.Join(opts.Statuses, ", "))
+	}
+
+	return textField("status", op, value, func(task tasks.Task) string { return task.Status }, func(a, b string) bool { return a == b })
+}
+
+// projectField matches sub-projects too with ":", like list --project;
+// "none" matches tasks without a project.
+func projectField(op, value string, _ Options) (predicate, error) {
+	get := func(task tasks.Task) string { return task.Project }
+
+	if isContains(op) {
+		return textField("project", op, value, get, nil)
+	}
+
+	if strings.EqualFold(value, "none") {
+		value = ""
+	} else {
+		project, err := tasks.NormalizeProject(value)
+		if err != nil {
+			return nil, err
+		}
+		value = project
+	}
+
+	equal := func(a, b string) bool { return a == b }
+	if op == ":" && value != "" {
+		equal = tasks.InProject
+	}
+
+	return textField("project", op, value, get, equal)
+}
+
+func descriptionField(op, value string, _ Options) (predicate, error) {
+	get := func(task tasks.Task) string { return task.Description }
+
+	if op == ":" {
+		op = "~"
+	}
+
+	return textField("description", op, value, get, strings.EqualFold)
+}
+
+// tagField matches tasks that have the tag ("=", ":") or lack it ("!="),
+// and with "~" tasks that have any tag matching the value.
+func tagField(op, value string, _ Options) (predicate, error) {
+	if isOrdering(op) {
+		return nil, operatorError{"tag"}
+	}
+
+	if isContains(op) {
+		matches, err := textMatcher(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(task tasks.Task, _ []tasks.Task) bool {
+			return slices.ContainsFunc(task.Tags, matches) == (op == "~")
+		}, nil
+	}
+
+	tag, err := tasks.NormalizeTag(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(task tasks.Task, _ []tasks.Task) bool {
+		return task.HasTag(tag) == (op != "!=")
+	}, nil
+}
+
+// priorityField accepts "priority:high+" as a short form of priority>=high.
+func priorityField(op, value string, _ Options) (predicate, error) {
+	if isContains(op) {
+		return nil, operatorError{"priority"}
+	}
+
+	if op == ":" && strings.HasSuffix(value, "+") {
+		op, value = ">=", strings.TrimSuffix(value, "+")
+	}
+
+	priority, err := tasks.ParsePriority(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(task tasks.Task, _ []tasks.Task) bool {
+		return holds(op, cmp.Compare(task.Priority, priority))
+	}, nil
+}
+
+func idField(op, value string, _ Options) (predicate, error) {
+	if isContains(op) {
+		return nil, operatorError{"id"}
+	}
+
+	ID, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task ID %q", value)
+	}
+
+	return func(task tasks.Task, _ []tasks.Task) bool {
+		return holds(op, cmp.Compare(task.ID, ID))
+	}, nil
+}
+
+// parentField matches the direct parent; "none" matches top-level tasks.
+func parentField(op, value string, _ Options) (predicate, error) {
+	if isOrdering(op) || isContains(op) {
+		return nil, operatorError{"parent"}
+	}
+
+	var parent int
+	if !strings.EqualFold(value, "none") {
+		var err error
+		if parent, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid task ID %q", value)
+		}
+	}
+
+	return func(task tasks.Task, _ []tasks.Task) bool {
+		return holds(op, cmp.Compare(task.ParentID, parent))
+	}, nil
+}
+
+// dateField compares a date field. A value without a time of day stands for
+// the whole day, so created>2026-01-01 means from January 2 on and
+// created=2026-01-01 means any time that day. Tasks without the date only
+// match "!=" (and "=none").
+func dateField(name string, get func(tasks.Task) time.Time) fieldFunc {
+	return func(op, value string, opts Options) (predicate, error) {
+		if isContains(op) {
+			return nil, operatorError{name}
+		}
+
+		if strings.EqualFold(value, "none") {
+			if isOrdering(op) {
+				return nil, operatorError{name}
+			}
+			return func(task tasks.Task, _ []tasks.Task) bool {
+				return get(task).IsZero() == (op != "!=")
+			}, nil
+		}
+
+		at, err := dateparse.Parse(value, opts.Now)
+		if err != nil {
+			return nil, err
+		}
+
+		start, end := at, at
+		if at.Hour() == 23 && at.Minute() == 59 && at.Second() == 59 && at.Nanosecond() == 0 {
+			start = time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+			end = start.AddDate(0, 0, 1)
+		}
+
+		return func(task tasks.Task, _ []tasks.Task) bool {
+			t := get(task)
+			if t.IsZero() {
+				return op == "!="
+			}
+
+			var c int
+			switch {
+			case t.Before(start):
+				c = -1
+			case t.Before(end) || t.Equal(start):
+				c = 0
+			default:
+				c = 1
+			}
+			return holds(op, c)
+		}, nil
+	}
+}
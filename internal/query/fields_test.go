@@ -0,0 +1,140 @@
+package query
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFields(t *testing.T) {
+	cases := []struct {
+		query string
+		want  []int
+	}{
+		{"priority:high+", []int{1, 4}},
+		{"priority<medium", []int{2, 3}},
+		{"priority!=none", []int{1, 2, 4}},
+		{"project:web", []int{3}},
+		{"project=web", []int{}},
+		{"project=web.auth", []int{3}},
+		{"project=none", []int{1}},
+		{"project~AUTH", []int{3}},
+		{"tag=urgent", []int{1, 3}},
+		{"tag!=urgent", []int{2, 4}},
+		{"tags~urg", []int{1, 3}},
+		{"description~login", []int{1, 3}},
+		{"desc:LOGIN", []int{1, 3}},
+		{"description!~login", []int{2, 4}},
+		{"description~/^login/", []int{3}},
+		{"description=\"write docs\"", []int{2}},
+		{"status~progress", []int{3}},
+		{"id>2", []int{3, 4}},
+		{"id<=2", []int{1, 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			if got := matching(t, c.query); !slices.Equal(got, c.want) {
+				t.Errorf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestDateField(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	all := []tasks.Task{
+		{ID: 1, CreatedAt: at(1, 9)},
+		{ID: 2, CreatedAt: at(1, 23)},
+		{ID: 3, CreatedAt: at(2, 0)},
+		{ID: 4, CreatedAt: at(3, 12), DueAt: at(5, 17)},
+	}
+
+	cases := []struct {
+		query string
+		want  []int
+	}{
+		{"created>2026-01-01", []int{3, 4}},
+		{"created>=2026-01-02", []int{3, 4}},
+		{"created=2026-01-01", []int{1, 2}},
+		{"created<2026-01-02", []int{1, 2}},
+		{"created<=2026-01-01", []int{1, 2}},
+		{"created!=2026-01-01", []int{3, 4}},
+		{`created>"2026-01-01 12:00"`, []int{2, 3, 4}},
+		{"due<2026-01-06", []int{4}},
+		{"due=none", []int{1, 2, 3}},
+		{"due!=none", []int{4}},
+		{"due!=2026-01-05", []int{1, 2, 3}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			q, err := Parse(c.query, Options{Now: testNow})
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+
+			got := []int{}
+			for _, task := range all {
+				if q.Match(task, all) {
+					got = append(got, task.ID)
+				}
+			}
+			if !slices.Equal(got, c.want) {
+				t.Errorf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
+
+	t.Run("Understands relative dates", func(t *testing.T) {
+		q, err := Parse("due=tomorrow", Options{Now: at(4, 10)})
+		if err != nil {
+			t.Fatalf("Parse returned error: %v", err)
+		}
+
+		if !q.Match(all[3], all) {
+			t.Errorf("Expected a task due tomorrow to match due=tomorrow")
+		}
+	})
+}
+
+func TestKeywords(t *testing.T) {
+	all := []tasks.Task{
+		{ID: 1, Status: "todo", DueAt: testNow.Add(-time.Hour)},
+		{ID: 2, Status: "todo", DependsOn: []int{1}},
+		{ID: 3, Status: "done", DependsOn: []int{1}},
+		{ID: 4, Status: "todo", Tags: []string{"x"}},
+	}
+
+	cases := []struct {
+		query string
+		want  []int
+	}{
+		{"ready", []int{1, 4}},
+		{"blocked", []int{2, 3}},
+		{"ready -x", []int{1}},
+		{"OVERDUE", []int{1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			q, err := Parse(c.query, Options{Now: testNow})
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+
+			got := []int{}
+			for _, task := range all {
+				if q.Match(task, all) {
+					got = append(got, task.ID)
+				}
+			}
+			if !slices.Equal(got, c.want) {
+				t.Errorf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,491 @@
+// Package query parses the filter language used by list and by every
+// command that takes a task selection, e.g.
+//
+//	status!=done AND (priority>=high OR +urgent) AND NOT project:infra
+package query
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options tell Parse about the context a query runs in.
+type Options struct {
+	// Now is the reference time for relative dates such as "tomorrow".
+	Now time.Time
+	// Statuses, when set, are the only status values a query may use.
+	Statuses []string
+	// Workflow decides which statuses count as finished for the ready,
+	// blocked and overdue keywords.
+	Workflow tasks.Workflow
+}
+
+// Query is a parsed query. It implements tasks.Matcher.
+type Query struct {
+	text string
+	root node
+}
+
+// Error reports a query that cannot be parsed. Pos is the byte offset of the
+// offending token in Query.
+type Error struct {
+	Query   string
+	Pos     int
+	Message string
+}
+
+// Error counts the column in characters, so the caret lines up under
+// queries with non-ASCII text.
+func (e *Error) Error() string {
+	column := utf8.RuneCountInString(e.Query[:min(e.Pos, len(e.Query))])
+	return fmt.Sprintf("%s at column %d\n  %s\n  %s^", e.Message, column+1, e.Query, strings.Repeat(" ", column))
+}
+
+func (e *Error) Is(target error) bool {
+	return target == tasks.ErrValidation
+}
+
+// Parse reads a query. Terms next to each other must all match; AND, OR and
+// NOT (in any case) and parentheses combine them explicitly.
+func Parse(text string, opts Options) (*Query, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	p := &parser{text: text, opts: opts}
+	if err := p.scan(); err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind == tokEOF {
+		return nil, p.errorAt(p.peek(), "empty query")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.text))
+	}
+
+	return &Query{text: text, root: root}, nil
+}
+
+func (q *Query) String() string {
+	return q.text
+}
+
+// Match reports whether task satisfies the query; all holds every task so
+// terms such as "ready" can look at dependencies.
+func (q *Query) Match(task tasks.Task, all []tasks.Task) bool {
+	return q.root.match(task, all)
+}
+
+// IDs returns the task IDs named one by one in ID terms that must hold (not
+// under OR or NOT), or nil if there are none.
+func (q *Query) IDs() []int {
+	named, _ := requiredIDs(q.root)
+	return named
+}
+
+// RangeIDs returns the task IDs that ranges such as 1-5 in ID terms that must
+// hold expand to, or nil if there are none.
+func (q *Query) RangeIDs() []int {
+	_, ranged := requiredIDs(q.root)
+	return ranged
+}
+
+// SingleID reports whether the query is nothing but one task ID.
+func (q *Query) SingleID() (int, bool) {
+	if n, ok := q.root.(idsNode); ok && len(n.IDs) == 1 && len(n.Ranged) == 0 {
+		return n.IDs[0], true
+	}
+
+	return 0, false
+}
+
+// OnlyExcludes reports whether the query does nothing but rule tasks out,
+// as in "-urgent" or "NOT 3", so it matches nearly every task.
+func (q *Query) OnlyExcludes() bool {
+	return onlyExcludes(q.root)
+}
+
+func onlyExcludes(n node) bool {
+	switch n := n.(type) {
+	case notNode:
+		return true
+	case andNode:
+		return !slices.ContainsFunc(n, func(child node) bool { return !onlyExcludes(child) })
+	case orNode:
+		return slices.ContainsFunc(n, onlyExcludes)
+	default:
+		return false
+	}
+}
+
+type node interface {
+	match(task tasks.Task, all []tasks.Task) bool
+}
+
+type andNode []node
+
+func (n andNode) match(task tasks.Task, all []tasks.Task) bool {
+	for _, child := range n {
+		if !child.match(task, all) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type orNode []node
+
+func (n orNode) match(task tasks.Task, all []tasks.Task) bool {
+	for _, child := range n {
+		if child.match(task, all) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type notNode struct{ node }
+
+func (n notNode) match(task tasks.Task, all []tasks.Task) bool {
+	return !n.node.match(task, all)
+}
+
+// idsNode matches the IDs named one by one and those from ranges.
+type idsNode struct{ IDs, Ranged []int }
+
+func (n idsNode) match(task tasks.Task, _ []tasks.Task) bool {
+	return slices.Contains(n.IDs, task.ID) || slices.Contains(n.Ranged, task.ID)
+}
+
+type predicate func(task tasks.Task, all []tasks.Task) bool
+
+func (p predicate) match(task tasks.Task, all []tasks.Task) bool {
+	return p(task, all)
+}
+
+func requiredIDs(n node) (named, ranged []int) {
+	switch n := n.(type) {
+	case idsNode:
+		return n.IDs, n.Ranged
+	case andNode:
+		for _, child := range n {
+			childNamed, childRanged := requiredIDs(child)
+			named = append(named, childNamed...)
+			ranged = append(ranged, childRanged...)
+		}
+		return named, ranged
+	default:
+		return nil, nil
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	text   string
+	opts   Options
+	tokens []token
+	next   int
+}
+
+// scan splits the query into parentheses and words. A word runs until
+// whitespace or a parenthesis outside double quotes, so a quoted value may
+// contain both.
+func (p *parser) scan() error {
+	for i := 0; i < len(p.text); {
+		r := rune(p.text[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			p.tokens = append(p.tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			p.tokens = append(p.tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		default:
+			start := i
+			for i < len(p.text) && !unicode.IsSpace(rune(p.text[i])) && p.text[i] != '(' && p.text[i] != ')' {
+				if p.text[i] == '"' {
+					end := strings.IndexByte(p.text[i+1:], '"')
+					if end < 0 {
+						return &Error{Query: p.text, Pos: i, Message: "unterminated quote"}
+					}
+					i += end + 1
+				}
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokWord, text: p.text[start:i], pos: start})
+		}
+	}
+
+	p.tokens = append(p.tokens, token{kind: tokEOF, pos: len(p.text)})
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+
+	return tok
+}
+
+func (p *parser) errorAt(tok token, message string) error {
+	return &Error{Query: p.text, Pos: tok.pos, Message: message}
+}
+
+func isKeyword(tok token, keyword string) bool {
+	return tok.kind == tokWord && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := orNode{left}
+	for isKeyword(p.peek(), "or") {
+		p.advance()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return nodes, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := andNode{left}
+	for {
+		tok := p.peek()
+		if tok.kind == tokEOF || tok.kind == tokRParen || isKeyword(tok, "or") {
+			break
+		}
+		if isKeyword(tok, "and") {
+			p.advance()
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return nodes, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok := p.advance()
+
+	switch {
+	case tok.kind == tokEOF:
+		return nil, p.errorAt(tok, "expected a term")
+	case tok.kind == tokRParen:
+		return nil, p.errorAt(tok, `unexpected ")"`)
+	case tok.kind == tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorAt(tok, "missing closing parenthesis")
+		}
+		p.advance()
+		return inner, nil
+	case isKeyword(tok, "not"):
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	case isKeyword(tok, "and"), isKeyword(tok, "or"):
+		return nil, p.errorAt(tok, fmt.Sprintf("expected a term before %s", strings.ToUpper(tok.text)))
+	default:
+		return p.parseTerm(tok)
+	}
+}
+
+// parseTerm reads a single word: an ID list, +tag, -tag, a keyword such as
+// "ready", or a comparison like created>2026-01-01.
+func (p *parser) parseTerm(tok token) (node, error) {
+	word := tok.text
+
+	switch {
+	case word[0] >= '0' && word[0] <= '9':
+		IDs, ranged, err := parseIDList(word)
+		if err != nil {
+			return nil, p.errorAt(tok, err.Error())
+		}
+		return idsNode{IDs, ranged}, nil
+	case (word[0] == '+' || word[0] == '-') && len(word) > 1 && !strings.ContainsAny(word, `=<>~:"`):
+		// -3 reads like "not task 3"; as a tag it would select every
+		// other task.
+		if strings.Trim(word[1:], "0123456789,-") == "" {
+			return nil, p.errorAt(tok, fmt.Sprintf("%q is not a tag; to leave out tasks by ID use NOT %s", word, word[1:]))
+		}
+		tag, err := tasks.NormalizeTag(word[1:])
+		if err != nil {
+			return nil, p.errorAt(tok, err.Error())
+		}
+		has := predicate(func(task tasks.Task, _ []tasks.Task) bool { return task.HasTag(tag) })
+		if word[0] == '-' {
+			return notNode{has}, nil
+		}
+		return has, nil
+	}
+
+	if keyword, ok := keywords[strings.ToLower(word)]; ok {
+		return keyword(p.opts), nil
+	}
+
+	field, op, value, opPos := splitComparison(word)
+	if op == "" {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected word %q (compare a field instead, e.g. description~%s)", word, word))
+	}
+	if field == "" {
+		return nil, p.errorAt(tok, fmt.Sprintf("missing field name before %q", op))
+	}
+
+	valueTok := token{kind: tokWord, text: value, pos: tok.pos + opPos + len(op)}
+	value, err := unquote(value)
+	if err != nil {
+		return nil, p.errorAt(valueTok, err.Error())
+	}
+	if value == "" {
+		return nil, p.errorAt(valueTok, fmt.Sprintf("missing value after %s%s", field, op))
+	}
+
+	compare, ok := fields[strings.ToLower(field)]
+	if !ok {
+		return nil, p.errorAt(tok, fmt.Sprintf("unknown field %q (known fields: %s)", field, fieldNames()))
+	}
+
+	pred, err := compare(op, value, p.opts)
+	if err != nil {
+		if opErr, ok := err.(operatorError); ok {
+			return nil, p.errorAt(token{pos: tok.pos + opPos}, fmt.Sprintf("operator %s cannot be used with %s", op, opErr.field))
+		}
+		return nil, p.errorAt(valueTok, err.Error())
+	}
+
+	return pred, nil
+}
+
+// operators are tried longest first, so "!=" is not read as "!" and "=".
+var operators = []string{"!=", "<=", ">=", "!~", "=", "<", ">", "~", ":"}
+
+// splitComparison finds the first operator outside quotes. opPos is the
+// byte offset of the operator in word; op is empty if there is none.
+func splitComparison(word string) (field, op, value string, opPos int) {
+	inQuotes := false
+	for i := 0; i < len(word); i++ {
+		if word[i] == '"' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if inQuotes {
+			continue
+		}
+
+		for _, candidate := range operators {
+			if strings.HasPrefix(word[i:], candidate) {
+				return word[:i], candidate, word[i+len(candidate):], i
+			}
+		}
+	}
+
+	return word, "", "", 0
+}
+
+// unquote drops the double quotes around quoted parts of a value.
+func unquote(value string) (string, error) {
+	if strings.Count(value, `"`)%2 != 0 {
+		return "", fmt.Errorf("unterminated quote")
+	}
+
+	return strings.ReplaceAll(value, `"`, ""), nil
+}
+
+// maxRangeSize caps how many IDs a single range such as 1-100 may expand
+// to, so a typo cannot select millions of IDs.
+const maxRangeSize = 10000
+
+// parseIDList expands a comma separated list of IDs and ranges: 1,4,7-12.
+// The IDs named one by one and those from ranges are returned apart.
+func parseIDList(term string) (IDs, ranged []int, err error) {
+	for part := range strings.SplitSeq(term, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+
+		from, err := strconv.Atoi(first)
+		to := from
+		if err == nil && isRange {
+			to, err = strconv.Atoi(last)
+		}
+		if err != nil || from < 1 || to < from {
+			return nil, nil, fmt.Errorf("invalid task ID or range %q", part)
+		}
+		if to-from >= maxRangeSize {
+			return nil, nil, fmt.Errorf("range %q is larger than %d tasks", part, maxRangeSize)
+		}
+
+		if !isRange {
+			IDs = append(IDs, from)
+			continue
+		}
+		for ID := from; ID <= to; ID++ {
+			ranged = append(ranged, ID)
+		}
+	}
+
+	return IDs, ranged, nil
+}
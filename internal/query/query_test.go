@@ -0,0 +1,223 @@
+package query
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+
+func testTasks() []tasks.Task {
+	return []tasks.Task{
+		{ID: 1, Description: "Fix login bug", Status: "todo", Priority: tasks.PriorityHigh, Tags: []string{"urgent"}},
+		{ID: 2, Description: "Write docs", Status: "done", Priority: tasks.PriorityLow, Project: "docs"},
+		{ID: 3, Description: "Login page redesign", Status: "in progress", Project: "web.auth", Tags: []string{"urgent"}},
+		{ID: 4, Description: "Clean up CI", Status: "todo", Priority: tasks.PriorityCritical, Project: "infra"},
+	}
+}
+
+// matching parses text and returns the IDs of the test tasks it matches.
+func matching(t *testing.T, text string) []int {
+	t.Helper()
+
+	q, err := Parse(text, Options{Now: testNow})
+	if err != nil {
+		t.Fatalf("Parse(%q) returned error: %v", text, err)
+	}
+
+	all := testTasks()
+	IDs := []int{}
+	for _, task := range all {
+		if q.Match(task, all) {
+			IDs = append(IDs, task.ID)
+		}
+	}
+
+	return IDs
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		query string
+		want  []int
+	}{
+		{"+urgent", []int{1, 3}},
+		{"-urgent", []int{2, 4}},
+		{"+urgent status=todo", []int{1}},
+		{"+urgent AND status=todo", []int{1}},
+		{"status=done OR project=infra", []int{2, 4}},
+		{"status!=done AND (priority>=high OR +urgent)", []int{1, 3, 4}},
+		{"status!=done and priority>=high or +urgent", []int{1, 3, 4}},
+		{"NOT +urgent OR status=todo", []int{1, 2, 4}},
+		{"not (+urgent OR status=todo)", []int{2}},
+		{"((status=todo))", []int{1, 4}},
+		{"1,3-4", []int{1, 3, 4}},
+		{"1-3 -urgent", []int{2}},
+		{`status="in progress"`, []int{3}},
+		{`description~"login bug"`, []int{1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			if got := matching(t, c.query); !slices.Equal(got, c.want) {
+				t.Errorf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
+
+	t.Run("Judges overdue at Now", func(t *testing.T) {
+		q, err := Parse("overdue", Options{Now: testNow})
+		if err != nil {
+			t.Fatalf("Parse returned error: %v", err)
+		}
+
+		early := tasks.Task{ID: 1, Status: "todo", DueAt: testNow.Add(-time.Hour)}
+		late := tasks.Task{ID: 2, Status: "todo", DueAt: testNow.Add(time.Hour)}
+		if !q.Match(early, nil) || q.Match(late, nil) {
+			t.Errorf("Expected only the task due before Now to be overdue")
+		}
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		query   string
+		pos     int
+		message string
+	}{
+		{"", 0, "empty query"},
+		{"foo>3", 0, `unknown field "foo"`},
+		{"status=todo tag>x", 15, "operator > cannot be used with tag"},
+		{"(status=todo", 0, "missing closing parenthesis"},
+		{"status=todo)", 11, `unexpected ")"`},
+		{"status=todo OR", 14, "expected a term"},
+		{"AND +x", 0, "expected a term before AND"},
+		{"fix", 0, `unexpected word "fix"`},
+		{"status=", 7, "missing value after status="},
+		{`description~"login`, 12, "unterminated quote"},
+		{"priority>urgent", 9, "invalid priority"},
+		{"1,x", 0, `invalid task ID or range "x"`},
+		{"status=todo -3", 12, `"-3" is not a tag`},
+		{"+4-6", 0, `"+4-6" is not a tag`},
+		{"description~/[/", 12, "invalid regular expression"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			_, err := Parse(c.query, Options{Now: testNow})
+
+			var queryErr *Error
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("Expected a query error, got %v", err)
+			}
+			if queryErr.Pos != c.pos {
+				t.Errorf("Expected position %d, got %d", c.pos, queryErr.Pos)
+			}
+			if !startsWith(queryErr.Message, c.message) {
+				t.Errorf("Expected message starting with %q, got %q", c.message, queryErr.Message)
+			}
+			if !errors.Is(err, tasks.ErrValidation) {
+				t.Errorf("Expected the error to be a validation error")
+			}
+		})
+	}
+
+	t.Run("Points at the token", func(t *testing.T) {
+		_, err := Parse("status=todo tag>x", Options{Now: testNow})
+
+		want := "operator > cannot be used with tag at column 16\n  status=todo tag>x\n                 ^"
+		if err == nil || err.Error() != want {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	})
+
+	t.Run("Counts the column in characters", func(t *testing.T) {
+		_, err := Parse("description~Übersicht tag>x", Options{Now: testNow})
+
+		want := "operator > cannot be used with tag at column 26\n  description~Übersicht tag>x\n  " + strings.Repeat(" ", 25) + "^"
+		if err == nil || err.Error() != want {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	})
+
+	t.Run("Rejects unknown statuses", func(t *testing.T) {
+		_, err := Parse("status=nope", Options{Now: testNow, Statuses: []string{"todo", "done"}})
+
+		var queryErr *Error
+		if !errors.As(err, &queryErr) || queryErr.Pos != 7 {
+			t.Errorf("Expected an error at the status value, got %v", err)
+		}
+	})
+}
+
+func TestOnlyExcludes(t *testing.T) {
+	cases := map[string]bool{
+		"-urgent":            true,
+		"NOT 3":              true,
+		"-urgent NOT +home":  true,
+		"-urgent OR +home":   true,
+		"-urgent +home":      false,
+		"status=todo NOT 3":  false,
+		"status!=done":       false,
+		"NOT (3 OR -urgent)": true,
+	}
+
+	for text, want := range cases {
+		t.Run(text, func(t *testing.T) {
+			q, err := Parse(text, Options{Now: testNow})
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+
+			if got := q.OnlyExcludes(); got != want {
+				t.Errorf("Expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func startsWith(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
+
+func TestQueryIDs(t *testing.T) {
+	cases := []struct {
+		query  string
+		IDs    []int
+		ranged []int
+		single bool
+	}{
+		{"3", []int{3}, nil, true},
+		{"1,4", []int{1, 4}, nil, false},
+		{"3 status=todo", []int{3}, nil, false},
+		{"(1 AND +x) 2", []int{1, 2}, nil, false},
+		{"1,3-5 8-9", []int{1}, []int{3, 4, 5, 8, 9}, false},
+		{"2-2", nil, []int{2}, false},
+		{"1 OR 2", nil, nil, false},
+		{"NOT 3", nil, nil, false},
+		{"status=todo", nil, nil, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			q, err := Parse(c.query, Options{Now: testNow})
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+
+			if IDs := q.IDs(); !slices.Equal(IDs, c.IDs) {
+				t.Errorf("Expected IDs %v, got %v", c.IDs, IDs)
+			}
+			if ranged := q.RangeIDs(); !slices.Equal(ranged, c.ranged) {
+				t.Errorf("Expected range IDs %v, got %v", c.ranged, ranged)
+			}
+			if _, single := q.SingleID(); single != c.single {
+				t.Errorf("Expected SingleID %v, got %v", c.single, single)
+			}
+		})
+	}
+}
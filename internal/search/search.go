@@ -0,0 +1,267 @@
+// Package search finds tasks by the words in their description, tags and
+// project, ranking the best matches first.
+package search
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options change how Search matches.
+type Options struct {
+	// Fuzzy also matches words one or two typos away from a search term.
+	Fuzzy bool
+}
+
+// Result is a task that matched, with its relevance and the matched words.
+type Result struct {
+	Task    tasks.Task `json:"task"`
+	Score   float64    `json:"score"`
+	Matches []Match    `json:"matches"`
+}
+
+// Match is a word that matched a search term. Start and End are byte offsets
+// into the field: the description, the project, or the tag named by Tag.
+type Match struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag,omitempty"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Term  string `json:"term"`
+}
+
+// fieldWeights make a word in the description count for more than the same
+// word in a tag or the project.
+var fieldWeights = map[string]float64{
+	"description": 1,
+	"tag":         0.8,
+	"project":     0.5,
+}
+
+// How well a word matches a term.
+const (
+	exactScore  = 1
+	prefixScore = 0.7
+	fuzzyScore  = 0.5
+)
+
+// Search returns the live tasks matching any of the words in text, best
+// first. A term matches a word that equals it or starts with it, ignoring
+// case, so "cert" finds "certificate". Terms that few tasks contain count
+// for more than common ones, and tasks matching more of the terms rank
+// higher.
+func Search(store tasks.Store, text string, opts Options) ([]Result, error) {
+	terms := Terms(text)
+	if len(terms) == 0 {
+		return nil, &tasks.ValidationError{Field: "search", Message: "no words to search for"}
+	}
+
+	list, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		task tasks.Task
+		// best holds the best weighted match score per term.
+		best    []float64
+		matches []Match
+	}
+
+	var candidates []candidate
+	found := make([]int, len(terms))
+	live := 0
+	for _, task := range list {
+		if task.IsDeleted() {
+			continue
+		}
+		live++
+
+		c := candidate{task: task, best: make([]float64, len(terms))}
+		for _, field := range fieldsOf(task) {
+			for _, word := range tokenize(field.text) {
+				for i, term := range terms {
+					score := matchScore(term, word.text, opts.Fuzzy)
+					if score == 0 {
+						continue
+					}
+
+					c.best[i] = max(c.best[i], score*fieldWeights[field.name])
+					c.matches = append(c.matches, Match{Field: field.name, Tag: field.tag, Start: word.start, End: word.end, Term: term})
+				}
+			}
+		}
+
+		if len(c.matches) == 0 {
+			continue
+		}
+		for i, best := range c.best {
+			if best > 0 {
+				found[i]++
+			}
+		}
+		candidates = append(candidates, c)
+	}
+
+	results := make([]Result, 0, len(candidates))
+	for _, c := range candidates {
+		var score float64
+		matched := 0
+		for i, best := range c.best {
+			if best > 0 {
+				score += best * math.Log(1+float64(live)/float64(found[i]))
+				matched++
+			}
+		}
+		score *= float64(matched) / float64(len(terms))
+
+		results = append(results, Result{Task: c.task, Score: math.Round(score*1000) / 1000, Matches: c.matches})
+	}
+
+	slices.SortStableFunc(results, func(a, b Result) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Task.ID, b.Task.ID))
+	})
+
+	return results, nil
+}
+
+// Terms splits text into lower case search terms, dropping repeats.
+func Terms(text string) []string {
+	var terms []string
+	for _, word := range tokenize(text) {
+		if !slices.Contains(terms, word.text) {
+			terms = append(terms, word.text)
+		}
+	}
+
+	return terms
+}
+
+type field struct {
+	name string
+	tag  string
+	text string
+}
+
+func fieldsOf(task tasks.Task) []field {
+	fields := []field{{name: "description", text: task.Description}}
+	for _, tag := range task.Tags {
+		fields = append(fields, field{name: "tag", tag: tag, text: tag})
+	}
+	if task.Project != "" {
+		fields = append(fields, field{name: "project", text: task.Project})
+	}
+
+	return fields
+}
+
+type word struct {
+	text       string
+	start, end int
+}
+
+// tokenize splits text into runs of letters and digits, lower cased, with
+// their byte offsets in text.
+func tokenize(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			words = append(words, word{text: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+
+	return words
+}
+
+func matchScore(term, word string, fuzzy bool) float64 {
+	switch {
+	case word == term:
+		return exactScore
+	case strings.HasPrefix(word, term) && utf8.RuneCountInString(term) >= 2:
+		return prefixScore
+	case fuzzy:
+		allowed := maxTypos(term)
+		if allowed == 0 {
+			return 0
+		}
+		if d := distance(term, word, allowed); d <= allowed {
+			return fuzzyScore / float64(d)
+		}
+	}
+
+	return 0
+}
+
+// maxTypos is how many edits a fuzzy match may need: none for short terms,
+// where a single typo gives another word, and at most two.
+func maxTypos(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the number of single character insertions, deletions,
+// substitutions and swaps of neighbours that turn a into b. It gives up
+// and returns limit+1 once the distance is known to exceed limit.
+func distance(a, b string, limit int) int {
+	s, t := []rune(a), []rune(b)
+	if abs(len(s)-len(t)) > limit {
+		return limit + 1
+	}
+
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(t)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testStore() *tasks.MemoryStore {
+	return tasks.NewMemoryStore(
+		tasks.Task{ID: 1, Description: "Renew the TLS certificate for api.example.com", Tags: []string{"infra"}},
+		tasks.Task{ID: 2, Description: "Rotate TLS keys"},
+		tasks.Task{ID: 3, Description: "Write the certification checklist", Project: "ops.security"},
+		tasks.Task{ID: 4, Description: "Fix login bug", Tags: []string{"tls"}},
+		tasks.Task{ID: 5, Description: "Old TLS notes", DeletedAt: time.Now()},
+	)
+}
+
+func resultIDs(results []Result) []int {
+	IDs := []int{}
+	for _, result := range results {
+		IDs = append(IDs, result.Task.ID)
+	}
+
+	return IDs
+}
+
+func TestSearch(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		fuzzy bool
+		want  []int
+	}{
+		{"Ignores case", "tls", false, []int{1, 2, 4}},
+		{"Ranks tasks matching more terms first", "the TLS cert", false, []int{1, 3, 2, 4}},
+		{"Matches word prefixes", "certif", false, []int{1, 3}},
+		{"Searches tags and projects", "security infra", false, []int{1, 3}},
+		{"Needs fuzzy for typos", "certifcate", false, []int{}},
+		{"Finds typos with fuzzy", "certifcate", true, []int{1}},
+		{"Allows swapped letters", "lgoin", true, []int{4}},
+		{"Keeps short terms exact", "tsl", true, []int{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			results, err := Search(testStore(), c.text, Options{Fuzzy: c.fuzzy})
+			if err != nil {
+				t.Fatalf("Search returned error: %v", err)
+			}
+
+			if got := resultIDs(results); !slices.Equal(got, c.want) {
+				t.Errorf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
+
+	t.Run("Reports where the terms matched", func(t *testing.T) {
+		results, err := Search(testStore(), "TLS infra", Options{})
+		if err != nil {
+			t.Fatalf("Search returned error: %v", err)
+		}
+
+		want := []Match{
+			{Field: "description", Start: 10, End: 13, Term: "tls"},
+			{Field: "tag", Tag: "infra", Start: 0, End: 5, Term: "infra"},
+		}
+		if results[0].Task.ID != 1 || !slices.Equal(results[0].Matches, want) {
+			t.Errorf("Expected task 1 with matches %+v, got %+v", want, results[0])
+		}
+	})
+
+	t.Run("Rejects text without words", func(t *testing.T) {
+		if _, err := Search(testStore(), " -- ", Options{}); !errors.Is(err, tasks.ErrValidation) {
+			t.Errorf("Expected ErrValidation, got %v", err)
+		}
+	})
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("Renew TLS-cert, renew tls! Über")
+	want := []string{"renew", "tls", "cert", "über"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"login", "login", 0},
+		{"login", "lgoin", 1},
+		{"login", "logon", 1},
+		{"login", "logins", 1},
+		{"certificate", "certifcate", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, c := range cases {
+		if got := distance(c.a, c.b, 3); got != c.want {
+			t.Errorf("distance(%q, %q): expected %d, got %d", c.a, c.b, c.want, got)
+		}
+	}
+
+	if got := distance("kitten", "sitting", 1); got != 2 {
+		t.Errorf("Expected distance to stop past the limit with 2, got %d", got)
+	}
+}
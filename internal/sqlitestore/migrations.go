@@ -0,0 +1,118 @@
+package sqlitestore
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrations are applied in order; a database at schema version N has run
+// the first N entries. Never edit an entry once released, append a new one.
+var migrations = []string{
+	`CREATE TABLE tasks (
+		id          INTEGER PRIMARY KEY,
+		description TEXT    NOT NULL,
+		status      TEXT    NOT NULL,
+		created_at  TEXT    NOT NULL,
+		updated_at  TEXT    NOT NULL
+	)`,
+	`CREATE INDEX tasks_status ON tasks (status)`,
+	`ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE tasks ADD COLUMN due_at TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE task_tags (
+		task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		tag     TEXT    NOT NULL,
+		PRIMARY KEY (task_id, tag)
+	)`,
+	`CREATE INDEX task_tags_tag ON task_tags (tag)`,
+	`ALTER TABLE tasks ADD COLUMN project TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX tasks_project ON tasks (project)`,
+	`ALTER TABLE tasks ADD COLUMN parent_id INTEGER NOT NULL DEFAULT 0`,
+	`CREATE INDEX tasks_parent ON tasks (parent_id)`,
+	`CREATE TABLE task_dependencies (
+		task_id    INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		depends_on INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+		PRIMARY KEY (task_id, depends_on)
+	)`,
+	`CREATE TABLE task_events (
+		task_id   INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		seq       INTEGER NOT NULL,
+		at        TEXT    NOT NULL,
+		field     TEXT    NOT NULL,
+		old_value TEXT    NOT NULL,
+		new_value TEXT    NOT NULL,
+		PRIMARY KEY (task_id, seq)
+	)`,
+	`ALTER TABLE tasks ADD COLUMN deleted_at TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE tasks ADD COLUMN uid TEXT NOT NULL DEFAULT ''`,
+	`CREATE UNIQUE INDEX tasks_uid ON tasks (uid) WHERE uid != ''`,
+	`CREATE TABLE store_meta (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`,
+}
+
+func SchemaVersion() int {
+	return len(migrations)
+}
+
+// migrate brings the schema up to date. It only takes the write lock when
+// migrations are pending, so opening a current database never waits for a
+// writer.
+func migrate(db *sql.DB) error {
+	version, err := schemaVersion(db)
+	if err != nil || version == len(migrations) {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL)`); err != nil {
+		return err
+	}
+
+	// Another process may have migrated while this one waited for the lock.
+	if version, err = schemaVersion(tx); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, i+1); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// schemaVersion is the number of migrations the database has run, 0 for a
+// new database.
+func schemaVersion(q queryRower) (int, error) {
+	var exists bool
+	err := q.QueryRow(`SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&exists)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var version int
+	if err := q.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, err
+	}
+
+	if version > len(migrations) {
+		return 0, fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+
+	return version, nil
+}
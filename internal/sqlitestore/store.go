@@ -0,0 +1,534 @@
+package sqlitestore
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	timeFormat  = time.RFC3339Nano
+	taskColumns = `id, description, status, priority, created_at, updated_at, due_at, project, parent_id, deleted_at, uid`
+)
+
+type Store struct {
+	db   *sql.DB
+	file string
+}
+
+func Open(file string, lockTimeout time.Duration) (*Store, error) {
+	if file == "" {
+		return nil, &tasks.ValidationError{Field: "file", Message: "filename cannot be empty"}
+	}
+
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", lockTimeout.Milliseconds()))
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", "synchronous(FULL)")
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+file+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, lockError(file, err)
+	}
+
+	return &Store{db: db, file: file}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Load() ([]tasks.Task, error) {
+	var list []tasks.Task
+	err := s.read(func(tx *sql.Tx) (err error) {
+		list, err = s.loadTasks(tx)
+		return err
+	})
+
+	return list, err
+}
+
+func (s *Store) Save(list []tasks.Task) error {
+	return s.Update(func([]tasks.Task) ([]tasks.Task, error) {
+		return list, nil
+	})
+}
+
+func (s *Store) Get(ID int) (tasks.Task, error) {
+	var task tasks.Task
+	err := s.read(func(tx *sql.Tx) (err error) {
+		task, err = s.get(tx, ID)
+		return err
+	})
+
+	return task, err
+}
+
+func (s *Store) get(tx *sql.Tx, ID int) (tasks.Task, error) {
+	row := tx.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, ID)
+
+	task, err := s.scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tasks.Task{}, &tasks.TaskNotFoundError{ID: ID}
+	}
+	if err != nil {
+		return tasks.Task{}, err
+	}
+
+	list := []tasks.Task{task}
+	if err := attachTags(tx, list); err != nil {
+		return tasks.Task{}, err
+	}
+	if err := attachDependencies(tx, list); err != nil {
+		return tasks.Task{}, err
+	}
+	if err := s.attachEvents(tx, list); err != nil {
+		return tasks.Task{}, err
+	}
+
+	return list[0], nil
+}
+
+func (s *Store) Update(fn func([]tasks.Task) ([]tasks.Task, error)) error {
+	return s.UpdateMeta(func(list []tasks.Task, _ *tasks.Meta) ([]tasks.Task, error) {
+		return fn(list)
+	})
+}
+
+func (s *Store) LoadMeta() (tasks.Meta, error) {
+	var meta tasks.Meta
+	err := s.read(func(tx *sql.Tx) error {
+		list, err := s.loadTasks(tx)
+		if err != nil {
+			return err
+		}
+
+		meta, err = s.loadMeta(tx, list)
+		return err
+	})
+
+	return meta, err
+}
+
+// read runs fn inside a read transaction, so the tasks and the rows attached
+// to them all come from one snapshot. In WAL mode it does not wait for
+// writers.
+func (s *Store) read(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return lockError(s.file, err)
+	}
+	defer tx.Rollback()
+
+	return lockError(s.file, fn(tx))
+}
+
+// UpdateMeta runs fn inside a write transaction and only writes the rows
+// that fn actually added, changed or removed.
+func (s *Store) UpdateMeta(fn func([]tasks.Task, *tasks.Meta) ([]tasks.Task, error)) error {
+	return lockError(s.file, s.updateMeta(fn))
+}
+
+func (s *Store) updateMeta(fn func([]tasks.Task, *tasks.Meta) ([]tasks.Task, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := s.loadTasks(tx)
+	if err != nil {
+		return err
+	}
+
+	meta, err := s.loadMeta(tx, before)
+	if err != nil {
+		return err
+	}
+
+	old := make(map[int]tasks.Task, len(before))
+	for _, task := range before {
+		old[task.ID] = task
+	}
+
+	after, err := fn(append([]tasks.Task{}, before...), &meta)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(after))
+	for _, task := range after {
+		if seen[task.ID] {
+			return fmt.Errorf("duplicate task ID %d", task.ID)
+		}
+		seen[task.ID] = true
+
+		if prev, ok := old[task.ID]; ok && reflect.DeepEqual(prev, task) {
+			continue
+		}
+
+		if err := writeTask(tx, task); err != nil {
+			return err
+		}
+	}
+
+	for ID := range old {
+		if !seen[ID] {
+			if _, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	meta.Observe(after)
+	if err := writeMeta(tx, "next_id", strconv.Itoa(meta.NextID)); err != nil {
+		return err
+	}
+
+	views, err := json.Marshal(meta.Views)
+	if err != nil {
+		return err
+	}
+	if err := writeMeta(tx, "views", string(views)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func writeMeta(tx *sql.Tx, key, value string) error {
+	_, err := tx.Exec(
+		`INSERT INTO store_meta (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		key, value,
+	)
+	return err
+}
+
+func (s *Store) loadMeta(q queryer, list []tasks.Task) (tasks.Meta, error) {
+	var meta tasks.Meta
+
+	rows, err := q.Query(`SELECT key, value FROM store_meta`)
+	if err != nil {
+		return meta, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return meta, err
+		}
+
+		switch key {
+		case "next_id":
+			if meta.NextID, err = strconv.Atoi(value); err != nil {
+				return meta, s.corrupt(fmt.Errorf("invalid next_id %q", value))
+			}
+		case "views":
+			if err := json.Unmarshal([]byte(value), &meta.Views); err != nil {
+				return meta, s.corrupt(fmt.Errorf("invalid views: %v", err))
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return meta, err
+	}
+
+	meta.Observe(list)
+	return meta, nil
+}
+
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (s *Store) loadTasks(q queryer) ([]tasks.Task, error) {
+	rows, err := q.Query(`SELECT ` + taskColumns + ` FROM tasks ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []tasks.Task{}
+	for rows.Next() {
+		task, err := s.scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := attachTags(q, list); err != nil {
+		return nil, err
+	}
+	if err := attachDependencies(q, list); err != nil {
+		return nil, err
+	}
+	if err := s.attachEvents(q, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func attachTags(q queryer, list []tasks.Task) error {
+	index := make(map[int]int, len(list))
+	for i, task := range list {
+		index[task.ID] = i
+	}
+
+	query := `SELECT task_id, tag FROM task_tags ORDER BY task_id, tag`
+	var args []any
+	if len(list) == 1 {
+		query = `SELECT task_id, tag FROM task_tags WHERE task_id = ? ORDER BY tag`
+		args = append(args, list[0].ID)
+	}
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ID int
+		var tag string
+		if err := rows.Scan(&ID, &tag); err != nil {
+			return err
+		}
+
+		if i, ok := index[ID]; ok {
+			list[i].Tags = append(list[i].Tags, tag)
+		}
+	}
+
+	return rows.Err()
+}
+
+func attachDependencies(q queryer, list []tasks.Task) error {
+	index := make(map[int]int, len(list))
+	for i, task := range list {
+		index[task.ID] = i
+	}
+
+	query := `SELECT task_id, depends_on FROM task_dependencies ORDER BY task_id, depends_on`
+	var args []any
+	if len(list) == 1 {
+		query = `SELECT task_id, depends_on FROM task_dependencies WHERE task_id = ? ORDER BY depends_on`
+		args = append(args, list[0].ID)
+	}
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ID, dep int
+		if err := rows.Scan(&ID, &dep); err != nil {
+			return err
+		}
+
+		if i, ok := index[ID]; ok {
+			list[i].DependsOn = append(list[i].DependsOn, dep)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Store) attachEvents(q queryer, list []tasks.Task) error {
+	index := make(map[int]int, len(list))
+	for i, task := range list {
+		index[task.ID] = i
+	}
+
+	query := `SELECT task_id, at, field, old_value, new_value FROM task_events ORDER BY task_id, seq`
+	var args []any
+	if len(list) == 1 {
+		query = `SELECT task_id, at, field, old_value, new_value FROM task_events WHERE task_id = ? ORDER BY seq`
+		args = append(args, list[0].ID)
+	}
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ID int
+		var at string
+		var event tasks.Event
+		if err := rows.Scan(&ID, &at, &event.Field, &event.Old, &event.New); err != nil {
+			return err
+		}
+
+		if event.At, err = parseTime(at); err != nil {
+			return s.corrupt(fmt.Errorf("task %d: invalid event time: %w", ID, err))
+		}
+
+		if i, ok := index[ID]; ok {
+			list[i].History = append(list[i].History, event)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Store) scanTask(row scanner) (tasks.Task, error) {
+	var task tasks.Task
+	var createdAt, updatedAt, dueAt, deletedAt string
+
+	if err := row.Scan(&task.ID, &task.Description, &task.Status, &task.Priority, &createdAt, &updatedAt, &dueAt, &task.Project, &task.ParentID, &deletedAt, &task.UID); err != nil {
+		return tasks.Task{}, err
+	}
+
+	var err error
+	if task.CreatedAt, err = parseTime(createdAt); err != nil {
+		return tasks.Task{}, s.corrupt(fmt.Errorf("task %d: invalid created_at: %w", task.ID, err))
+	}
+	if task.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return tasks.Task{}, s.corrupt(fmt.Errorf("task %d: invalid updated_at: %w", task.ID, err))
+	}
+	if task.DueAt, err = parseTime(dueAt); err != nil {
+		return tasks.Task{}, s.corrupt(fmt.Errorf("task %d: invalid due_at: %w", task.ID, err))
+	}
+	if task.DeletedAt, err = parseTime(deletedAt); err != nil {
+		return tasks.Task{}, s.corrupt(fmt.Errorf("task %d: invalid deleted_at: %w", task.ID, err))
+	}
+
+	return task, nil
+}
+
+// formatTime stores the zero time as an empty string so optional
+// timestamps stay readable in the database.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(timeFormat)
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(timeFormat, value)
+}
+
+func (s *Store) corrupt(err error) error {
+	return &tasks.CorruptStoreError{File: s.file, Err: err}
+}
+
+// lockError reports a database that another connection kept busy for longer
+// than the busy timeout as tasks.ErrLocked, like a locked task file.
+func lockError(file string, err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		if code := sqliteErr.Code() & 0xff; code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED {
+			return fmt.Errorf("%w: gave up waiting for %s: %v", tasks.ErrLocked, file, err)
+		}
+	}
+
+	return err
+}
+
+func writeTask(tx *sql.Tx, task tasks.Task) error {
+	if err := upsertTask(tx, task); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, task.ID); err != nil {
+		return err
+	}
+
+	for _, tag := range task.Tags {
+		if _, err := tx.Exec(`INSERT INTO task_tags (task_id, tag) VALUES (?, ?)`, task.ID, tag); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM task_dependencies WHERE task_id = ?`, task.ID); err != nil {
+		return err
+	}
+
+	for _, dep := range task.DependsOn {
+		if _, err := tx.Exec(`INSERT INTO task_dependencies (task_id, depends_on) VALUES (?, ?)`, task.ID, dep); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM task_events WHERE task_id = ?`, task.ID); err != nil {
+		return err
+	}
+
+	for seq, event := range task.History {
+		if _, err := tx.Exec(
+			`INSERT INTO task_events (task_id, seq, at, field, old_value, new_value) VALUES (?, ?, ?, ?, ?, ?)`,
+			task.ID, seq, formatTime(event.At), event.Field, event.Old, event.New,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func upsertTask(tx *sql.Tx, task tasks.Task) error {
+	_, err := tx.Exec(
+		`INSERT INTO tasks (`+taskColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			description = excluded.description,
+			status = excluded.status,
+			priority = excluded.priority,
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at,
+			due_at = excluded.due_at,
+			project = excluded.project,
+			parent_id = excluded.parent_id,
+			deleted_at = excluded.deleted_at,
+			uid = excluded.uid`,
+		task.ID,
+		task.Description,
+		task.Status,
+		int(task.Priority),
+		formatTime(task.CreatedAt),
+		formatTime(task.UpdatedAt),
+		formatTime(task.DueAt),
+		task.Project,
+		task.ParentID,
+		formatTime(task.DeletedAt),
+		task.UID,
+	)
+	return err
+}
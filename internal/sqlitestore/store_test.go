@@ -0,0 +1,401 @@
+package sqlitestore
+
+import (
+	"TaskTrackerCLI/internal/tasks"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTempStore(t *testing.T) (*Store, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	store, err := Open(path, time.Second)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return store, path
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("Applies all migrations", func(t *testing.T) {
+		store, _ := openTempStore(t)
+
+		var version int
+		if err := store.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+			t.Fatalf("failed to read schema version: %v", err)
+		}
+
+		if version != SchemaVersion() {
+			t.Fatalf("Expected schema version %d, got %d", SchemaVersion(), version)
+		}
+	})
+
+	t.Run("Reopening does not re-run migrations", func(t *testing.T) {
+		store, path := openTempStore(t)
+		store.Close()
+
+		reopened, err := Open(path, time.Second)
+		if err != nil {
+			t.Fatalf("Open returned error on reopen: %v", err)
+		}
+		defer reopened.Close()
+
+		var count int
+		if err := reopened.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&count); err != nil {
+			t.Fatalf("failed to count migrations: %v", err)
+		}
+
+		if count != SchemaVersion() {
+			t.Fatalf("Expected %d migration rows, got %d", SchemaVersion(), count)
+		}
+	})
+
+	t.Run("Opens a current database while another connection writes", func(t *testing.T) {
+		store, path := openTempStore(t)
+
+		tx, err := store.db.Begin()
+		if err != nil {
+			t.Fatalf("Begin returned error: %v", err)
+		}
+		defer tx.Rollback()
+
+		reader, err := Open(path, 50*time.Millisecond)
+		if err != nil {
+			t.Fatalf("Open returned error: %v", err)
+		}
+		defer reader.Close()
+	})
+
+	t.Run("Refuses database from newer binary", func(t *testing.T) {
+		store, path := openTempStore(t)
+		if _, err := store.db.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, SchemaVersion()+1); err != nil {
+			t.Fatalf("failed to bump schema version: %v", err)
+		}
+		store.Close()
+
+		if _, err := Open(path, time.Second); err == nil || !strings.Contains(err.Error(), "newer") {
+			t.Fatalf("Expected error about newer schema, got %v", err)
+		}
+	})
+
+	t.Run("Returns error when filename is empty", func(t *testing.T) {
+		_, err := Open("", time.Second)
+		if err == nil || err.Error() != "filename cannot be empty" {
+			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
+		}
+	})
+}
+
+func TestStoreLocked(t *testing.T) {
+	store, path := openTempStore(t)
+
+	waiting, err := Open(path, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer waiting.Close()
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		t.Fatalf("Begin returned error: %v", err)
+	}
+	defer tx.Rollback()
+
+	err = waiting.Update(func(list []tasks.Task) ([]tasks.Task, error) { return list, nil })
+	if !errors.Is(err, tasks.ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+
+	if _, err := waiting.Load(); err != nil {
+		t.Errorf("Expected reads to go on while another connection writes, got %v", err)
+	}
+	if _, err := waiting.LoadMeta(); err != nil {
+		t.Errorf("Expected reads to go on while another connection writes, got %v", err)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	store, _ := openTempStore(t)
+
+	created := time.Date(2025, 1, 12, 15, 4, 5, 123456789, time.FixedZone("CET", 3600))
+	want := []tasks.Task{
+		{ID: 1, UID: "0190b6f2-5c3a-7d4e-8f10-112233445566", Description: "Buy groceries", Status: "todo", CreatedAt: created, Tags: []string{"errands", "home"}, Project: "home.kitchen", DependsOn: []int{2}},
+		{ID: 2, Description: "Cook dinner", Status: "done", Priority: tasks.PriorityHigh, CreatedAt: created, UpdatedAt: created.Add(time.Hour), DueAt: created.Add(48 * time.Hour), ParentID: 1, DeletedAt: created.Add(72 * time.Hour), History: []tasks.Event{
+			{At: created, Field: "status", New: "todo"},
+			{At: created.Add(time.Hour), Field: "status", Old: "todo", New: "done"},
+		}},
+	}
+
+	if err := store.Save(want); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d tasks, got %d", len(want), len(got))
+	}
+
+	for i := range want {
+		if got[i].ID != want[i].ID || got[i].Description != want[i].Description || got[i].Status != want[i].Status || got[i].Priority != want[i].Priority || got[i].Project != want[i].Project || got[i].ParentID != want[i].ParentID || got[i].UID != want[i].UID {
+			t.Errorf("task[%d]: got %+v, want %+v", i, got[i], want[i])
+		}
+		if !slices.Equal(got[i].Tags, want[i].Tags) {
+			t.Errorf("task[%d] tags: got %v, want %v", i, got[i].Tags, want[i].Tags)
+		}
+		if !slices.Equal(got[i].DependsOn, want[i].DependsOn) {
+			t.Errorf("task[%d] dependencies: got %v, want %v", i, got[i].DependsOn, want[i].DependsOn)
+		}
+		if !slices.EqualFunc(got[i].History, want[i].History, func(a, b tasks.Event) bool {
+			return a.At.Equal(b.At) && a.Field == b.Field && a.Old == b.Old && a.New == b.New
+		}) {
+			t.Errorf("task[%d] history: got %v, want %v", i, got[i].History, want[i].History)
+		}
+		if !got[i].CreatedAt.Equal(want[i].CreatedAt) || !got[i].UpdatedAt.Equal(want[i].UpdatedAt) || !got[i].DueAt.Equal(want[i].DueAt) || !got[i].DeletedAt.Equal(want[i].DeletedAt) {
+			t.Errorf("task[%d] timestamps: got %v/%v/%v, want %v/%v/%v", i, got[i].CreatedAt, got[i].UpdatedAt, got[i].DueAt, want[i].CreatedAt, want[i].UpdatedAt, want[i].DueAt)
+		}
+	}
+}
+
+func TestStoreUpdate(t *testing.T) {
+	t.Run("Applies additions, changes and deletions", func(t *testing.T) {
+		store, _ := openTempStore(t)
+
+		if err := store.Save([]tasks.Task{
+			{ID: 1, Description: "Buy groceries", Status: "todo"},
+			{ID: 2, Description: "Cook dinner", Status: "todo"},
+		}); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+
+		err := store.Update(func(list []tasks.Task) ([]tasks.Task, error) {
+			list[0].Status = "done"
+			return append(list[:1], tasks.Task{ID: 3, Description: "Clean kitchen", Status: "todo"}), nil
+		})
+		if err != nil {
+			t.Fatalf("Update returned error: %v", err)
+		}
+
+		got, err := store.Load()
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+
+		if len(got) != 2 || got[0].ID != 1 || got[0].Status != "done" || got[1].ID != 3 {
+			t.Fatalf("unexpected tasks after update: %+v", got)
+		}
+	})
+
+	t.Run("Rewrites tags and removes them with their task", func(t *testing.T) {
+		store, _ := openTempStore(t)
+
+		if err := store.Save([]tasks.Task{
+			{ID: 1, Description: "Fix login", Tags: []string{"backend", "blocked"}},
+			{ID: 2, Description: "Style page", Tags: []string{"frontend"}},
+		}); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+
+		if _, err := tasks.EditTags(store, 1, []string{"urgent"}, []string{"blocked"}); err != nil {
+			t.Fatalf("EditTags returned error: %v", err)
+		}
+
+		if _, err := tasks.DeleteTask(store, 2, tasks.ChildrenRefuse); err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+		if _, err := tasks.PurgeTasks(store, 0, time.Now()); err != nil {
+			t.Fatalf("PurgeTasks returned error: %v", err)
+		}
+
+		task, err := store.Get(1)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+		if !slices.Equal(task.Tags, []string{"backend", "urgent"}) {
+			t.Errorf("tags: got %v, want [backend urgent]", task.Tags)
+		}
+
+		var orphans int
+		if err := store.db.QueryRow(`SELECT COUNT(*) FROM task_tags WHERE task_id = 2`).Scan(&orphans); err != nil {
+			t.Fatalf("failed to count tags: %v", err)
+		}
+		if orphans != 0 {
+			t.Errorf("Expected tags of deleted task to be removed, found %d", orphans)
+		}
+	})
+
+	t.Run("Drops dependencies on purged tasks", func(t *testing.T) {
+		store, _ := openTempStore(t)
+
+		if err := store.Save([]tasks.Task{
+			{ID: 1, Description: "Design schema", Status: "todo"},
+			{ID: 2, Description: "Write migration", Status: "todo", DependsOn: []int{1}},
+		}); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+
+		if _, err := tasks.DeleteTask(store, 1, tasks.ChildrenRefuse); err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+		if _, err := tasks.PurgeTasks(store, 0, time.Now()); err != nil {
+			t.Fatalf("PurgeTasks returned error: %v", err)
+		}
+
+		task, err := store.Get(2)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+		if len(task.DependsOn) != 0 {
+			t.Errorf("Expected no dependencies, got %v", task.DependsOn)
+		}
+	})
+
+	t.Run("Rolls back when fn returns error", func(t *testing.T) {
+		store, _ := openTempStore(t)
+
+		if err := store.Save([]tasks.Task{{ID: 1, Description: "Buy groceries"}}); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+
+		err := store.Update(func(list []tasks.Task) ([]tasks.Task, error) {
+			return nil, errors.New("boom")
+		})
+		if err == nil || err.Error() != "boom" {
+			t.Fatalf("Expected error %q, got %v", "boom", err)
+		}
+
+		got, _ := store.Load()
+		if len(got) != 1 {
+			t.Fatalf("Expected 1 task, got %d", len(got))
+		}
+	})
+
+	t.Run("Works with service functions", func(t *testing.T) {
+		store, _ := openTempStore(t)
+
+		if _, err := tasks.AddTask(store, "Buy groceries", tasks.AddOptions{}); err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+
+		if _, err := tasks.MarkTaskDone(store, 1, tasks.ChildrenRefuse); err != nil {
+			t.Fatalf("MarkTaskDone returned error: %v", err)
+		}
+
+		task, err := store.Get(1)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+
+		if task.Status != "done" {
+			t.Errorf("Expected status %q, got %q", "done", task.Status)
+		}
+	})
+}
+
+func TestStoreMeta(t *testing.T) {
+	store, path := openTempStore(t)
+
+	if _, err := tasks.AddTask(store, "Buy groceries", tasks.AddOptions{}); err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+	second, err := tasks.AddTask(store, "Cook dinner", tasks.AddOptions{WithUID: true})
+	if err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+	if _, err := tasks.DeleteTask(store, 2, tasks.ChildrenRefuse); err != nil {
+		t.Fatalf("DeleteTask returned error: %v", err)
+	}
+	if _, err := tasks.PurgeTasks(store, 0, time.Now()); err != nil {
+		t.Fatalf("PurgeTasks returned error: %v", err)
+	}
+	store.Close()
+
+	reopened, err := Open(path, time.Second)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer reopened.Close()
+
+	meta, err := reopened.LoadMeta()
+	if err != nil {
+		t.Fatalf("LoadMeta returned error: %v", err)
+	}
+	if meta.NextID != 3 {
+		t.Errorf("Expected next ID 3, got %d", meta.NextID)
+	}
+
+	task, err := tasks.AddTask(reopened, "Clean kitchen", tasks.AddOptions{})
+	if err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+	if task.ID != 3 {
+		t.Errorf("Expected ID 3, got %d", task.ID)
+	}
+
+	if _, err := tasks.SaveView(reopened, "mine", tasks.View{Args: []string{"+urgent", "--sort", "due"}}); err != nil {
+		t.Fatalf("SaveView returned error: %v", err)
+	}
+	if meta, err = reopened.LoadMeta(); err != nil || len(meta.Views["mine"].Args) != 3 {
+		t.Errorf("Expected the saved view, got %v (%v)", meta.Views, err)
+	}
+
+	if second.UID == "" {
+		t.Fatal("Expected a UID")
+	}
+	err = reopened.Save([]tasks.Task{{ID: 1, UID: second.UID}, {ID: 2, UID: second.UID}})
+	if err == nil {
+		t.Error("Expected duplicate UIDs to be rejected")
+	}
+}
+
+func TestStoreGet(t *testing.T) {
+	store, _ := openTempStore(t)
+
+	_, err := store.Get(99)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Expected error to mention %q, got %v", "not found", err)
+	}
+}
+
+func TestCopyFromJSON(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "tasks.json")
+
+	created := time.Date(2025, 1, 12, 15, 4, 5, 0, time.UTC)
+	if err := tasks.Save(jsonFile, []tasks.Task{
+		{ID: 1, Description: "Buy groceries", Status: "todo", CreatedAt: created, Tags: []string{"errands", "home"}, Project: "home.kitchen"},
+		{ID: 4, Description: "Cook dinner", Status: "in progress", CreatedAt: created},
+	}); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	store, _ := openTempStore(t)
+
+	n, err := tasks.CopyTasks(store, tasks.NewJSONStore(jsonFile))
+	if err != nil {
+		t.Fatalf("CopyTasks returned error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("Expected 2 tasks copied, got %d", n)
+	}
+
+	task, err := store.Get(4)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	if task.Status != "in progress" || !task.CreatedAt.Equal(created) {
+		t.Errorf("unexpected migrated task: %+v", task)
+	}
+}
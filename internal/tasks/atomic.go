@@ -0,0 +1,86 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const backupSuffix = ".bak"
+
+func BackupFile(file string) string {
+	return file + backupSuffix
+}
+
+// writeFileAtomic replaces file with data so that readers only ever see the
+// old or the new contents. The previous generation is kept in BackupFile(file).
+func writeFileAtomic(file string, data []byte, perm os.FileMode) error {
+	previous, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if len(previous) > 0 {
+		if err := replaceFile(BackupFile(file), previous, perm); err != nil {
+			return err
+		}
+	}
+
+	return replaceFile(file, data, perm)
+}
+
+func replaceFile(file string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(file)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(file)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(err)
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		return cleanup(err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, file); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+func syncDir(dir string) error {
+	// Windows cannot fsync a directory; the rename is already durable there.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Run("Writes new file without backup", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "tasks.json")
+
+		if err := writeFileAtomic(filename, []byte("first"), 0o644); err != nil {
+			t.Fatalf("writeFileAtomic returned error: %v", err)
+		}
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+
+		if string(data) != "first" {
+			t.Errorf("got %q, want %q", data, "first")
+		}
+
+		if _, err := os.Stat(BackupFile(filename)); !os.IsNotExist(err) {
+			t.Errorf("Expected no backup file, got err %v", err)
+		}
+	})
+
+	t.Run("Keeps previous generation as backup", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "tasks.json")
+
+		for _, content := range []string{"first", "second", "third"} {
+			if err := writeFileAtomic(filename, []byte(content), 0o644); err != nil {
+				t.Fatalf("writeFileAtomic returned error: %v", err)
+			}
+		}
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if string(data) != "third" {
+			t.Errorf("got %q, want %q", data, "third")
+		}
+
+		backup, err := os.ReadFile(BackupFile(filename))
+		if err != nil {
+			t.Fatalf("failed to read backup: %v", err)
+		}
+		if string(backup) != "second" {
+			t.Errorf("backup: got %q, want %q", backup, "second")
+		}
+	})
+
+	t.Run("Leaves no temp files behind", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "tasks.json")
+
+		for _, content := range []string{"first", "second"} {
+			if err := writeFileAtomic(filename, []byte(content), 0o644); err != nil {
+				t.Fatalf("writeFileAtomic returned error: %v", err)
+			}
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("failed to read dir: %v", err)
+		}
+
+		for _, entry := range entries {
+			if strings.Contains(entry.Name(), ".tmp-") {
+				t.Errorf("unexpected temp file %q", entry.Name())
+			}
+		}
+	})
+
+	t.Run("Fails without touching file when directory is missing", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "missing", "tasks.json")
+
+		if err := writeFileAtomic(filename, []byte("data"), 0o644); err == nil {
+			t.Fatal("Expected error for missing directory, got nil")
+		}
+	})
+}
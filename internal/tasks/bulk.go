@@ -0,0 +1,137 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Selection picks the tasks a bulk command applies to. A task is selected
+// when it is one of IDs or RangeIDs (if any are given) and matches Filter.
+type Selection struct {
+	IDs []int
+	// RangeIDs come from ranges such as 1-5. Unlike IDs, the ones that do
+	// not exist are skipped, so a range may span deleted tasks.
+	RangeIDs []int
+	Filter   Filter
+	// Trashed selects from the trash instead of the live tasks.
+	Trashed bool
+}
+
+// Single returns the ID when the selection is exactly one plain task ID.
+func (s Selection) Single() (int, bool) {
+	if len(s.IDs) != 1 || len(s.RangeIDs) > 0 || !s.Filter.isZero() {
+		return 0, false
+	}
+
+	return s.IDs[0], true
+}
+
+// Resolve returns the selected task IDs in ascending order. Every one of IDs
+// must exist, in the trash when Trashed is set and outside it otherwise.
+func (s Selection) Resolve(tasks []Task) ([]int, error) {
+	for _, ID := range s.IDs {
+		if i := indexOfAny(tasks, ID); i < 0 || tasks[i].IsDeleted() != s.Trashed {
+			return nil, &TaskNotFoundError{ID: ID}
+		}
+	}
+
+	limited := len(s.IDs) > 0 || len(s.RangeIDs) > 0
+	selected := []int{}
+	for _, task := range tasks {
+		if task.IsDeleted() != s.Trashed {
+			continue
+		}
+		if limited && !slices.Contains(s.IDs, task.ID) && !slices.Contains(s.RangeIDs, task.ID) {
+			continue
+		}
+		if !s.Filter.selects(task, tasks) {
+			continue
+		}
+
+		selected = append(selected, task.ID)
+	}
+
+	slices.Sort(selected)
+	return selected, nil
+}
+
+func (f Filter) isZero() bool {
+	return f.Status == "" && f.Priority == PriorityNone && !f.Overdue && f.DueBefore.IsZero() &&
+		len(f.Tags) == 0 && len(f.ExcludeTags) == 0 && f.Project == "" && !f.Ready && f.Query == nil
+}
+
+// BulkFunc applies a command to one task in store and returns every task it
+// changed, including subtasks it cascaded to. now is the same for every task
+// of the command.
+type BulkFunc func(store Store, ID int, now time.Time) ([]Task, error)
+
+// Bulk applies fn to each of IDs in a single store update, so either every
+// task is changed or none is. Subtasks are handled before their parents, and
+// a task already changed by fn for an earlier ID is skipped. The changed
+// tasks are returned in ID order.
+func Bulk(store Store, IDs []int, fn BulkFunc) ([]Task, error) {
+	return bulk(store, func([]Task) ([]int, error) { return IDs, nil }, fn)
+}
+
+// BulkSelection is Bulk for the tasks sel picks at the time of the update.
+// If they differ from want, the IDs the preview showed, it fails with a
+// conflict instead of changing tasks nobody saw.
+func BulkSelection(store Store, sel Selection, want []int, fn BulkFunc) ([]Task, error) {
+	return bulk(store, func(tasks []Task) ([]int, error) {
+		IDs, err := sel.Resolve(tasks)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(IDs, want) {
+			return nil, &ConflictError{Message: fmt.Sprintf("the selection changed since the preview: it now matches tasks %s instead of %s", JoinIDs(IDs, ", "), JoinIDs(want, ", "))}
+		}
+
+		return IDs, nil
+	}, fn)
+}
+
+func bulk(store Store, resolve func(tasks []Task) ([]int, error), fn BulkFunc) ([]Task, error) {
+	var changed []Task
+	err := store.UpdateMeta(func(tasks []Task, meta *Meta) ([]Task, error) {
+		IDs, err := resolve(tasks)
+		if err != nil {
+			return nil, err
+		}
+
+		mem := &MemoryStore{tasks: tasks, meta: *meta}
+		now := time.Now()
+
+		done := map[int]bool{}
+		for _, ID := range subtasksFirst(tasks, IDs) {
+			if done[ID] {
+				continue
+			}
+
+			affected, err := fn(mem, ID, now)
+			if err != nil {
+				return nil, err
+			}
+			for _, task := range affected {
+				done[task.ID] = true
+			}
+		}
+
+		changed = nil
+		for _, task := range mem.tasks {
+			if done[task.ID] {
+				changed = append(changed, task)
+			}
+		}
+		slices.SortFunc(changed, func(a, b Task) int { return cmp.Compare(a.ID, b.ID) })
+
+		*meta = mem.meta
+		return mem.tasks, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
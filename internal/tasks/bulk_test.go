@@ -0,0 +1,217 @@
+package tasks
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSelectionResolve(t *testing.T) {
+	list := []Task{
+		{ID: 1, Status: "todo", Tags: []string{"sprint42"}},
+		{ID: 2, Status: "done", Tags: []string{"sprint42"}},
+		{ID: 3, Status: "todo"},
+		{ID: 4, Status: "todo", Tags: []string{"sprint42"}},
+		{ID: 5, Status: "todo", Tags: []string{"sprint42"}, DeletedAt: time.Now()},
+	}
+
+	t.Run("Combines IDs and filters", func(t *testing.T) {
+		sel := Selection{IDs: []int{1, 2, 3, 4}, Filter: Filter{Status: "todo", Tags: []string{"sprint42"}}}
+
+		IDs, err := sel.Resolve(list)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if !slices.Equal(IDs, []int{1, 4}) {
+			t.Errorf("Expected [1 4], got %v", IDs)
+		}
+	})
+
+	t.Run("Selects from the trash only when asked", func(t *testing.T) {
+		sel := Selection{Filter: Filter{Tags: []string{"sprint42"}}}
+
+		if IDs, _ := sel.Resolve(list); !slices.Equal(IDs, []int{1, 2, 4}) {
+			t.Errorf("Expected live tasks [1 2 4], got %v", IDs)
+		}
+
+		sel.Trashed = true
+		if IDs, _ := sel.Resolve(list); !slices.Equal(IDs, []int{5}) {
+			t.Errorf("Expected trashed task [5], got %v", IDs)
+		}
+	})
+
+	t.Run("Applies the query", func(t *testing.T) {
+		sel := Selection{Filter: Filter{Query: matchFunc(func(task Task, all []Task) bool {
+			return len(all) == 5 && task.ID%2 == 0
+		})}}
+
+		if IDs, _ := sel.Resolve(list); !slices.Equal(IDs, []int{2, 4}) {
+			t.Errorf("Expected [2 4], got %v", IDs)
+		}
+		if _, ok := sel.Single(); ok {
+			t.Errorf("Expected a query not to count as a single ID")
+		}
+	})
+
+	t.Run("Reports missing IDs", func(t *testing.T) {
+		sel := Selection{IDs: []int{3, 5}}
+
+		if _, err := sel.Resolve(list); !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected ErrTaskNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Skips missing IDs from ranges", func(t *testing.T) {
+		sel := Selection{RangeIDs: []int{3, 4, 5, 6}}
+
+		IDs, err := sel.Resolve(list)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+		if !slices.Equal(IDs, []int{3, 4}) {
+			t.Errorf("Expected [3 4], got %v", IDs)
+		}
+		if _, ok := sel.Single(); ok {
+			t.Errorf("Expected a range not to count as a single ID")
+		}
+	})
+}
+
+type matchFunc func(task Task, all []Task) bool
+
+func (f matchFunc) Match(task Task, all []Task) bool {
+	return f(task, all)
+}
+
+func TestBulk(t *testing.T) {
+	markDone := func(store Store, ID int, now time.Time) ([]Task, error) {
+		task, err := MarkTaskDone(store, ID, ChildrenRefuse)
+		return []Task{task}, err
+	}
+
+	t.Run("Applies to every selected task", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"}, Task{ID: 2, Status: "todo"}, Task{ID: 3, Status: "todo"})
+
+		changed, err := Bulk(store, []int{1, 3}, markDone)
+		if err != nil {
+			t.Fatalf("Bulk returned error: %v", err)
+		}
+		if !equalIDs(ids(changed), []int{1, 3}) {
+			t.Errorf("Expected changed tasks [1 3], got %v", ids(changed))
+		}
+
+		task, _ := store.Get(2)
+		if task.Status != "todo" {
+			t.Errorf("Expected task 2 untouched, got %q", task.Status)
+		}
+	})
+
+	t.Run("Changes nothing when one task fails", func(t *testing.T) {
+		store := subtaskStore()
+
+		if _, err := Bulk(store, []int{1, 5}, markDone); !errors.Is(err, ErrConflict) {
+			t.Fatalf("Expected ErrConflict, got %v", err)
+		}
+
+		task, _ := store.Get(5)
+		if task.Status != "todo" {
+			t.Errorf("Expected task 5 to stay todo, got %q", task.Status)
+		}
+	})
+
+	t.Run("Handles subtasks before their parents", func(t *testing.T) {
+		store := subtaskStore()
+
+		if _, err := Bulk(store, []int{1, 3, 4}, markDone); err != nil {
+			t.Fatalf("Bulk returned error: %v", err)
+		}
+
+		deleted, err := Bulk(store, []int{1, 2, 3, 4}, func(store Store, ID int, now time.Time) ([]Task, error) {
+			return DeleteTask(store, ID, ChildrenRefuse)
+		})
+		if err != nil {
+			t.Fatalf("Bulk returned error: %v", err)
+		}
+		if !equalIDs(ids(deleted), []int{1, 2, 3, 4}) {
+			t.Errorf("Expected deleted [1 2 3 4], got %v", ids(deleted))
+		}
+	})
+
+	t.Run("Skips tasks a cascade already changed", func(t *testing.T) {
+		store := subtaskStore()
+
+		deleted, err := Bulk(store, []int{1, 3}, func(store Store, ID int, now time.Time) ([]Task, error) {
+			return DeleteTask(store, ID, ChildrenCascade)
+		})
+		if err != nil {
+			t.Fatalf("Bulk returned error: %v", err)
+		}
+		if !equalIDs(ids(deleted), []int{1, 2, 3, 4}) {
+			t.Errorf("Expected deleted [1 2 3 4], got %v", ids(deleted))
+		}
+	})
+
+	t.Run("Selects again when applying the change", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"}, Task{ID: 2, Status: "todo"}, Task{ID: 3, Status: "done"})
+		sel := Selection{Filter: Filter{Status: "todo"}}
+
+		list, _ := store.Load()
+		preview, err := sel.Resolve(list)
+		if err != nil {
+			t.Fatalf("Resolve returned error: %v", err)
+		}
+
+		if _, err := SetStatus(store, DefaultWorkflow(), 3, "todo", StatusOptions{}); err != nil {
+			t.Fatalf("SetStatus returned error: %v", err)
+		}
+
+		if _, err := BulkSelection(store, sel, preview, markDone); !errors.Is(err, ErrConflict) {
+			t.Fatalf("Expected ErrConflict, got %v", err)
+		}
+		if task, _ := store.Get(1); task.Status != "todo" {
+			t.Errorf("Expected task 1 to stay todo, got %q", task.Status)
+		}
+
+		changed, err := BulkSelection(store, sel, []int{1, 2, 3}, markDone)
+		if err != nil {
+			t.Fatalf("BulkSelection returned error: %v", err)
+		}
+		if !equalIDs(ids(changed), []int{1, 2, 3}) {
+			t.Errorf("Expected changed [1 2 3], got %v", ids(changed))
+		}
+	})
+
+	t.Run("Deletes at one time, so the tasks are restored together", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"}, Task{ID: 2, Status: "todo", ParentID: 1})
+
+		_, err := Bulk(store, []int{1, 2}, func(store Store, ID int, now time.Time) ([]Task, error) {
+			return DeleteTaskAt(store, ID, ChildrenRefuse, now)
+		})
+		if err != nil {
+			t.Fatalf("Bulk returned error: %v", err)
+		}
+
+		restored, err := RestoreTask(store, 1)
+		if err != nil {
+			t.Fatalf("RestoreTask returned error: %v", err)
+		}
+		if !equalIDs(ids(restored), []int{1, 2}) {
+			t.Errorf("Expected restored [1 2], got %v", ids(restored))
+		}
+	})
+
+	t.Run("Records one undo entry", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"}, Task{ID: 2, Status: "todo"})
+		journal := newTestJournal(t)
+
+		if _, err := Bulk(journal.Wrap(store, "mark-done 1,2"), []int{1, 2}, markDone); err != nil {
+			t.Fatalf("Bulk returned error: %v", err)
+		}
+
+		undo, _, _ := journal.Entries()
+		if len(undo) != 1 || len(undo[0].Changes) != 2 {
+			t.Fatalf("Expected one entry with 2 changes, got %+v", undo)
+		}
+	})
+}
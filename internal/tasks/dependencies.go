@@ -0,0 +1,125 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlockedBy returns the IDs of the tasks that task depends on and that are
+// not closed yet. Dependencies on tasks that no longer exist are ignored.
+func BlockedBy(w Workflow, tasks []Task, task Task) []int {
+	var blocking []int
+	for _, ID := range task.DependsOn {
+		if i, err := indexOf(tasks, ID); err == nil && !w.IsClosed(tasks[i].Status) {
+			blocking = append(blocking, ID)
+		}
+	}
+
+	return blocking
+}
+
+// AddDependencies records that task ID cannot start before the tasks in on
+// are done. It refuses dependencies that would create a cycle.
+func AddDependencies(store Store, ID int, on []int) (Task, error) {
+	var updated Task
+	err := store.Update(func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dep := range on {
+			if dep == ID {
+				return nil, &ValidationError{Field: "depends_on", Message: fmt.Sprintf("task %d cannot depend on itself", ID)}
+			}
+			if _, err := indexOf(tasks, dep); err != nil {
+				return nil, err
+			}
+			if path := dependencyPath(tasks, dep, ID); path != nil {
+				return nil, &ConflictError{
+					ID:      ID,
+					Message: "dependency cycle: " + JoinIDs(append([]int{ID}, path...), " -> "),
+				}
+			}
+
+			if !slices.Contains(tasks[i].DependsOn, dep) {
+				tasks[i].DependsOn = append(tasks[i].DependsOn, dep)
+			}
+		}
+
+		slices.Sort(tasks[i].DependsOn)
+		tasks[i].UpdatedAt = time.Now()
+		updated = tasks[i]
+		return tasks, nil
+	})
+	if err != nil {
+		return Task{}, err
+	}
+
+	return updated, nil
+}
+
+func RemoveDependencies(store Store, ID int, on []int) (Task, error) {
+	return updateTask(store, ID, func(task *Task) {
+		task.DependsOn = withoutIDs(task.DependsOn, on)
+		task.UpdatedAt = time.Now()
+	})
+}
+
+// dependencyPath returns the chain of dependencies leading from task from to
+// task to (both included), or nil if to is not reachable.
+func dependencyPath(tasks []Task, from, to int) []int {
+	visited := map[int]bool{}
+
+	var walk func(ID int) []int
+	walk = func(ID int) []int {
+		if ID == to {
+			return []int{ID}
+		}
+		if visited[ID] {
+			return nil
+		}
+		visited[ID] = true
+
+		i, err := indexOf(tasks, ID)
+		if err != nil {
+			return nil
+		}
+
+		for _, dep := range tasks[i].DependsOn {
+			if path := walk(dep); path != nil {
+				return append([]int{ID}, path...)
+			}
+		}
+
+		return nil
+	}
+
+	return walk(from)
+}
+
+// JoinIDs formats IDs as text separated by sep.
+func JoinIDs(IDs []int, sep string) string {
+	parts := make([]string, len(IDs))
+	for i, ID := range IDs {
+		parts[i] = strconv.Itoa(ID)
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// withoutIDs returns IDs minus remove, or nil when nothing is left so tasks
+// without dependencies serialize without a depends_on field.
+func withoutIDs(IDs, remove []int) []int {
+	var kept []int
+	for _, ID := range IDs {
+		if !slices.Contains(remove, ID) {
+			kept = append(kept, ID)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,133 @@
+package tasks
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddDependencies(t *testing.T) {
+	t.Run("Records dependencies sorted and once", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1}, Task{ID: 2}, Task{ID: 3})
+
+		if _, err := AddDependencies(store, 3, []int{2, 1}); err != nil {
+			t.Fatalf("AddDependencies returned error: %v", err)
+		}
+		task, err := AddDependencies(store, 3, []int{1})
+		if err != nil {
+			t.Fatalf("AddDependencies returned error: %v", err)
+		}
+
+		if !slices.Equal(task.DependsOn, []int{1, 2}) {
+			t.Errorf("Expected dependencies [1 2], got %v", task.DependsOn)
+		}
+	})
+
+	t.Run("Rejects cycles", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1}, Task{ID: 2, DependsOn: []int{1}}, Task{ID: 3, DependsOn: []int{2}})
+
+		_, err := AddDependencies(store, 1, []int{3})
+		if !errors.Is(err, ErrConflict) {
+			t.Fatalf("Expected ErrConflict, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "1 -> 3 -> 2 -> 1") {
+			t.Errorf("Expected cycle path in error, got %q", err)
+		}
+	})
+
+	t.Run("Rejects self and missing dependencies", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1})
+
+		if _, err := AddDependencies(store, 1, []int{1}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected ErrValidation, got %v", err)
+		}
+		if _, err := AddDependencies(store, 1, []int{9}); !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected ErrTaskNotFound, got %v", err)
+		}
+	})
+}
+
+func TestRemoveDependencies(t *testing.T) {
+	store := NewMemoryStore(Task{ID: 1}, Task{ID: 2}, Task{ID: 3, DependsOn: []int{1, 2}})
+
+	task, err := RemoveDependencies(store, 3, []int{1, 2})
+	if err != nil {
+		t.Fatalf("RemoveDependencies returned error: %v", err)
+	}
+	if task.DependsOn != nil {
+		t.Errorf("Expected no dependencies, got %v", task.DependsOn)
+	}
+}
+
+func TestBlockedTasks(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "done"},
+		Task{ID: 2, Status: "todo"},
+		Task{ID: 3, Status: "todo", DependsOn: []int{1, 2}},
+		Task{ID: 4, Status: "todo", DependsOn: []int{1}},
+	)
+
+	t.Run("Refuses to start a blocked task", func(t *testing.T) {
+		if _, err := MarkTaskInProgress(store, 3, false); !errors.Is(err, ErrConflict) {
+			t.Fatalf("Expected ErrConflict, got %v", err)
+		}
+		if _, err := MarkTaskInProgress(store, 4, false); err != nil {
+			t.Errorf("Expected unblocked task to start, got %v", err)
+		}
+	})
+
+	t.Run("Lists ready and blocked tasks", func(t *testing.T) {
+		result, err := ListTasks(store, Filter{})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+		if !slices.Equal(result.Blocked[3], []int{2}) || len(result.Blocked) != 1 {
+			t.Errorf("Expected only task 3 blocked by [2], got %v", result.Blocked)
+		}
+
+		result, err = ListTasks(store, Filter{Ready: true})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+		if !equalIDs(ids(result.Tasks), []int{4, 2}) {
+			t.Errorf("Expected ready tasks [4 2], got %v", ids(result.Tasks))
+		}
+	})
+
+	t.Run("Starts a blocked task when forced", func(t *testing.T) {
+		task, err := MarkTaskInProgress(store, 3, true)
+		if err != nil {
+			t.Fatalf("MarkTaskInProgress returned error: %v", err)
+		}
+		if task.Status != "in progress" {
+			t.Errorf("Expected status %q, got %q", "in progress", task.Status)
+		}
+	})
+
+	t.Run("Deleted tasks no longer block", func(t *testing.T) {
+		if _, err := DeleteTask(store, 2, ChildrenRefuse); err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+
+		result, err := ListTasks(store, Filter{})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+		if len(result.Blocked) != 0 {
+			t.Errorf("Expected no blocked tasks, got %v", result.Blocked)
+		}
+	})
+
+	t.Run("Purging a task drops it from dependency lists", func(t *testing.T) {
+		if _, err := PurgeTasks(store, 0, time.Now()); err != nil {
+			t.Fatalf("PurgeTasks returned error: %v", err)
+		}
+
+		task, _ := store.Get(3)
+		if !slices.Equal(task.DependsOn, []int{1}) {
+			t.Errorf("Expected dependencies [1], got %v", task.DependsOn)
+		}
+	})
+}
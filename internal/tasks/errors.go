@@ -0,0 +1,104 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTaskNotFound = errors.New("task not found")
+	ErrValidation   = errors.New("invalid input")
+	ErrCorruptStore = errors.New("task store is corrupt")
+	ErrConflict     = errors.New("operation conflicts with other tasks")
+)
+
+type TaskNotFoundError struct {
+	ID int
+}
+
+func (e *TaskNotFoundError) Error() string {
+	return fmt.Sprintf("task with ID %d not found", e.ID)
+}
+
+func (e *TaskNotFoundError) Is(target error) bool {
+	return target == ErrTaskNotFound
+}
+
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ConflictError reports an operation refused because of the state of other
+// tasks, such as deleting a task that still has subtasks.
+type ConflictError struct {
+	ID      int
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// CorruptStoreError reports stored data that cannot be decoded. Offset is
+// the byte position of the problem when the backend knows it, otherwise 0.
+type CorruptStoreError struct {
+	File   string
+	Offset int64
+	Err    error
+}
+
+func (e *CorruptStoreError) Error() string {
+	if e.Offset > 0 {
+		return fmt.Sprintf("%s is corrupt at byte %d: %v", e.File, e.Offset, e.Err)
+	}
+
+	return fmt.Sprintf("%s is corrupt: %v", e.File, e.Err)
+}
+
+func (e *CorruptStoreError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CorruptStoreError) Is(target error) bool {
+	return target == ErrCorruptStore
+}
+
+// UnsupportedVersionError reports a task file written in a format newer than
+// this binary understands.
+type UnsupportedVersionError struct {
+	File    string
+	Version int
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("%s uses file format version %d, but this version of task-cli only supports up to %d; please upgrade task-cli", e.File, e.Version, FileVersion)
+}
+
+func corruptJSON(file string, err error) error {
+	corrupt := &CorruptStoreError{File: file, Err: err}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		corrupt.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		corrupt.Offset = typeErr.Offset
+	}
+
+	return corrupt
+}
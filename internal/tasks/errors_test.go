@@ -0,0 +1,95 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTaskNotFoundError(t *testing.T) {
+	store := NewMemoryStore(Task{ID: 1, Description: "Buy groceries", Status: "todo"})
+
+	_, err := MarkTaskDone(store, 42, ChildrenRefuse)
+
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("Expected ErrTaskNotFound, got %v", err)
+	}
+
+	var notFound *TaskNotFoundError
+	if !errors.As(err, &notFound) || notFound.ID != 42 {
+		t.Fatalf("Expected TaskNotFoundError with ID 42, got %#v", err)
+	}
+
+	if err.Error() != "task with ID 42 not found" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	t.Run("Empty description", func(t *testing.T) {
+		_, err := AddTask(NewMemoryStore(), "", AddOptions{})
+
+		var validation *ValidationError
+		if !errors.As(err, &validation) || validation.Field != "description" {
+			t.Fatalf("Expected ValidationError for description, got %#v", err)
+		}
+
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected error to match ErrValidation")
+		}
+	})
+
+	t.Run("Empty filename", func(t *testing.T) {
+		_, err := NewJSONStore("").Load()
+
+		var validation *ValidationError
+		if !errors.As(err, &validation) || validation.Field != "file" {
+			t.Fatalf("Expected ValidationError for file, got %#v", err)
+		}
+	})
+}
+
+func TestCorruptStoreError(t *testing.T) {
+	t.Run("Invalid JSON reports offset", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "tasks.json")
+		if err := os.WriteFile(filename, []byte(`[{"id": 1,,}]`), 0o644); err != nil {
+			t.Fatalf("Failed to write temp file: %v", err)
+		}
+
+		_, err := Load(filename)
+
+		if !errors.Is(err, ErrCorruptStore) {
+			t.Fatalf("Expected ErrCorruptStore, got %v", err)
+		}
+
+		var corrupt *CorruptStoreError
+		if !errors.As(err, &corrupt) {
+			t.Fatalf("Expected CorruptStoreError, got %#v", err)
+		}
+
+		if corrupt.File != filename || corrupt.Offset != 11 {
+			t.Errorf("unexpected file/offset: %q/%d", corrupt.File, corrupt.Offset)
+		}
+
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Expected wrapped json.SyntaxError")
+		}
+	})
+
+	t.Run("Wrong field type reports offset", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "tasks.json")
+		if err := os.WriteFile(filename, []byte(`[{"id": "one"}]`), 0o644); err != nil {
+			t.Fatalf("Failed to write temp file: %v", err)
+		}
+
+		_, err := Load(filename)
+
+		var corrupt *CorruptStoreError
+		if !errors.As(err, &corrupt) || corrupt.Offset == 0 {
+			t.Fatalf("Expected CorruptStoreError with offset, got %#v", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"slices"
+	"time"
+)
+
+// Event records one change to a task. Field is "status" or "description";
+// the creation of a task is a status event with an empty Old value.
+type Event struct {
+	At    time.Time `json:"at"`
+	Field string    `json:"field"`
+	Old   string    `json:"old"`
+	New   string    `json:"new"`
+}
+
+// record appends an event to the task's history. The history is clipped
+// first so the append never writes into a backing array shared with the
+// caller's copy of the task list.
+func (t *Task) record(at time.Time, field, old, new string) {
+	t.History = append(slices.Clip(t.History), Event{At: at, Field: field, Old: old, New: new})
+}
+
+// setStatus changes the status, recording the change and touching UpdatedAt
+// if the status actually differs.
+func (t *Task) setStatus(status string, now time.Time) {
+	if t.Status == status {
+		return
+	}
+
+	t.record(now, "status", t.Status, status)
+	t.Status = status
+	t.UpdatedAt = now
+}
+
+// CycleTime is the time from when work on a task first started ("in
+// progress") until it was last closed. It reports false for tasks that are
+// not closed or were never started.
+func CycleTime(w Workflow, task Task) (time.Duration, bool) {
+	if !w.IsClosed(task.Status) {
+		return 0, false
+	}
+
+	var started, finished time.Time
+	for _, event := range task.History {
+		if event.Field != "status" {
+			continue
+		}
+
+		if event.New == "in progress" && started.IsZero() {
+			started = event.At
+		}
+		if w.IsClosed(event.New) {
+			finished = event.At
+		}
+	}
+
+	if started.IsZero() || finished.IsZero() || finished.Before(started) {
+		return 0, false
+	}
+
+	return finished.Sub(started), true
+}
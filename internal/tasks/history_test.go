@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	store := NewMemoryStore()
+
+	task, err := AddTask(store, "Fix login", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+
+	if _, err := MarkTaskInProgress(store, task.ID, false); err != nil {
+		t.Fatalf("MarkTaskInProgress returned error: %v", err)
+	}
+	if _, err := UpdateTask(store, task.ID, describe("Fix login redirect")); err != nil {
+		t.Fatalf("UpdateTask returned error: %v", err)
+	}
+	if _, err := MarkTaskDone(store, task.ID, ChildrenRefuse); err != nil {
+		t.Fatalf("MarkTaskDone returned error: %v", err)
+	}
+	if _, err := MarkTaskDone(store, task.ID, ChildrenRefuse); err != nil {
+		t.Fatalf("MarkTaskDone returned error: %v", err)
+	}
+
+	task, _ = store.Get(task.ID)
+
+	want := []Event{
+		{Field: "status", Old: "", New: "todo"},
+		{Field: "status", Old: "todo", New: "in progress"},
+		{Field: "description", Old: "Fix login", New: "Fix login redirect"},
+		{Field: "status", Old: "in progress", New: "done"},
+	}
+
+	if len(task.History) != len(want) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(want), len(task.History), task.History)
+	}
+
+	for i, event := range task.History {
+		if event.Field != want[i].Field || event.Old != want[i].Old || event.New != want[i].New {
+			t.Errorf("event[%d]: got %+v, want %+v", i, event, want[i])
+		}
+		if event.At.IsZero() {
+			t.Errorf("event[%d] has no timestamp", i)
+		}
+	}
+
+	if task.UpdatedAt.Before(task.History[3].At) {
+		t.Errorf("Expected UpdatedAt to be touched by status change, got %v", task.UpdatedAt)
+	}
+}
+
+func TestCycleTime(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	task := Task{Status: "done", History: []Event{
+		{At: start.Add(-time.Hour), Field: "status", New: "todo"},
+		{At: start, Field: "status", Old: "todo", New: "in progress"},
+		{At: start.Add(2 * time.Hour), Field: "status", Old: "in progress", New: "todo"},
+		{At: start.Add(3 * time.Hour), Field: "status", Old: "todo", New: "in progress"},
+		{At: start.Add(5 * time.Hour), Field: "status", Old: "in progress", New: "done"},
+	}}
+
+	got, ok := CycleTime(Workflow{}, task)
+	if !ok || got != 5*time.Hour {
+		t.Errorf("Expected cycle time 5h, got %v (ok=%v)", got, ok)
+	}
+
+	t.Run("Unknown for unfinished or never started tasks", func(t *testing.T) {
+		if _, ok := CycleTime(Workflow{}, Task{Status: "in progress", History: task.History[:2]}); ok {
+			t.Error("Expected no cycle time for unfinished task")
+		}
+		if _, ok := CycleTime(Workflow{}, Task{Status: "done", History: []Event{{At: start, Field: "status", New: "done"}}}); ok {
+			t.Error("Expected no cycle time for task that was never started")
+		}
+	})
+}
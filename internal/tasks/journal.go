@@ -0,0 +1,316 @@
+package tasks
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+)
+
+const (
+	journalSuffix       = ".undo.json"
+	DefaultJournalLimit = 20
+)
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// JournalEntry is one recorded mutation: the command that made it and the
+// tasks it changed.
+type JournalEntry struct {
+	At      time.Time `json:"at"`
+	Command string    `json:"command"`
+	Changes []Change  `json:"changes"`
+}
+
+// Change holds a task before and after a mutation. Before is nil for an
+// added task and After is nil for a deleted one.
+type Change struct {
+	Before *Task `json:"before,omitempty"`
+	After  *Task `json:"after,omitempty"`
+}
+
+type journalFile struct {
+	Undo []JournalEntry `json:"undo"`
+	Redo []JournalEntry `json:"redo"`
+}
+
+// Journal keeps the last Limit mutations of a store in a file so they can
+// be undone and redone.
+type Journal struct {
+	file string
+
+	Limit       int
+	LockTimeout time.Duration
+}
+
+func NewJournal(file string) *Journal {
+	return &Journal{file: file, Limit: DefaultJournalLimit, LockTimeout: DefaultLockTimeout}
+}
+
+// JournalFile returns the journal path used for a task file.
+func JournalFile(taskFile string) string {
+	return taskFile + journalSuffix
+}
+
+// Entries returns the undoable and redoable entries, most recent first.
+func (j *Journal) Entries() (undo, redo []JournalEntry, err error) {
+	data, err := j.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slices.Reverse(data.Undo)
+	slices.Reverse(data.Redo)
+	return data.Undo, data.Redo, nil
+}
+
+// Wrap returns a store that records every Update and Save made through it
+// under the given command. Recording a new mutation discards the redo list.
+func (j *Journal) Wrap(store Store, command string) Store {
+	return &journaledStore{Store: store, journal: j, command: command}
+}
+
+// Undo reverts the most recent entry, provided none of its tasks changed
+// since it was recorded.
+func (j *Journal) Undo(store Store) (JournalEntry, error) {
+	return j.replay(store, true)
+}
+
+// Redo reapplies the most recently undone entry.
+func (j *Journal) Redo(store Store) (JournalEntry, error) {
+	return j.replay(store, false)
+}
+
+// replay pops the last entry of the undo (or redo) list, applies it in that
+// direction and pushes it onto the other list.
+func (j *Journal) replay(store Store, undo bool) (JournalEntry, error) {
+	var entry JournalEntry
+	err := j.withLock(func(data *journalFile) error {
+		src, dst, empty := &data.Undo, &data.Redo, ErrNothingToUndo
+		if !undo {
+			src, dst, empty = &data.Redo, &data.Undo, ErrNothingToRedo
+		}
+
+		if len(*src) == 0 {
+			return empty
+		}
+		entry = (*src)[len(*src)-1]
+
+		err := store.Update(func(tasks []Task) ([]Task, error) {
+			return applyChanges(tasks, entry.Changes, undo)
+		})
+		if err != nil {
+			return err
+		}
+
+		*src = (*src)[:len(*src)-1]
+		*dst = append(*dst, entry)
+		return nil
+	})
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (j *Journal) record(data *journalFile, entry JournalEntry) {
+	data.Undo = append(data.Undo, entry)
+	if j.Limit > 0 && len(data.Undo) > j.Limit {
+		data.Undo = data.Undo[len(data.Undo)-j.Limit:]
+	}
+	data.Redo = nil
+}
+
+// withLock runs fn with the journal loaded and locked, and saves it if fn
+// succeeds.
+func (j *Journal) withLock(fn func(data *journalFile) error) error {
+	lock, err := acquireLock(LockFile(j.file), j.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	data, err := j.load()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&data); err != nil {
+		return err
+	}
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(j.file, encoded, 0644)
+}
+
+func (j *Journal) load() (journalFile, error) {
+	var data journalFile
+
+	content, err := os.ReadFile(j.file)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(content) == 0) {
+		return data, nil
+	}
+	if err != nil {
+		return data, err
+	}
+
+	if err := json.Unmarshal(content, &data); err != nil {
+		return data, corruptJSON(j.file, err)
+	}
+
+	return data, nil
+}
+
+type journaledStore struct {
+	Store
+	journal *Journal
+	command string
+}
+
+func (s *journaledStore) Save(tasks []Task) error {
+	return s.Update(func([]Task) ([]Task, error) {
+		return tasks, nil
+	})
+}
+
+func (s *journaledStore) Update(fn func(tasks []Task) ([]Task, error)) error {
+	return s.UpdateMeta(func(tasks []Task, _ *Meta) ([]Task, error) {
+		return fn(tasks)
+	})
+}
+
+// UpdateMeta journals the task changes only. Metadata such as the ID counter
+// is not reverted by undo, so undoing an add never frees its ID for reuse.
+func (s *journaledStore) UpdateMeta(fn func(tasks []Task, meta *Meta) ([]Task, error)) error {
+	return s.journal.withLock(func(data *journalFile) error {
+		var changes []Change
+		err := s.Store.UpdateMeta(func(tasks []Task, meta *Meta) ([]Task, error) {
+			before := cloneTasks(tasks)
+
+			after, err := fn(tasks, meta)
+			if err != nil {
+				return nil, err
+			}
+
+			changes = diffTasks(before, after)
+			return after, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(changes) > 0 {
+			s.journal.record(data, JournalEntry{At: time.Now(), Command: s.command, Changes: changes})
+		}
+		return nil
+	})
+}
+
+// cloneTasks copies tasks deeply enough that fn can append to or sort their
+// slices in place without changing the copy.
+func cloneTasks(tasks []Task) []Task {
+	clones := slices.Clone(tasks)
+	for i := range clones {
+		clones[i].Tags = slices.Clone(clones[i].Tags)
+		clones[i].DependsOn = slices.Clone(clones[i].DependsOn)
+		clones[i].History = slices.Clone(clones[i].History)
+	}
+
+	return clones
+}
+
+func diffTasks(before, after []Task) []Change {
+	var changes []Change
+
+	old := make(map[int]Task, len(before))
+	for _, task := range before {
+		old[task.ID] = task
+	}
+
+	seen := make(map[int]bool, len(after))
+	for _, task := range after {
+		seen[task.ID] = true
+
+		prev, ok := old[task.ID]
+		switch {
+		case !ok:
+			changes = append(changes, Change{After: &task})
+		case !sameTask(&prev, &task):
+			changes = append(changes, Change{Before: &prev, After: &task})
+		}
+	}
+
+	for _, task := range before {
+		if !seen[task.ID] {
+			changes = append(changes, Change{Before: &task})
+		}
+	}
+
+	return changes
+}
+
+// applyChanges moves the tasks of changes to their Before state (undo) or
+// their After state (redo). It refuses if any of them is no longer in the
+// state it was left in.
+func applyChanges(tasks []Task, changes []Change, undo bool) ([]Task, error) {
+	for _, change := range changes {
+		expected, target := change.After, change.Before
+		if !undo {
+			expected, target = change.Before, change.After
+		}
+
+		ID := cmp.Or(change.Before, change.After).ID
+		i := indexOfAny(tasks, ID)
+
+		var current *Task
+		if i >= 0 {
+			current = &tasks[i]
+		}
+		if !sameTask(current, expected) {
+			return nil, &ConflictError{
+				ID:      ID,
+				Message: fmt.Sprintf("task %d was changed since; cannot revert", ID),
+			}
+		}
+
+		switch {
+		case target == nil:
+			tasks = slices.Delete(tasks, i, i+1)
+		case current == nil:
+			tasks = append(tasks, *target)
+		default:
+			tasks[i] = *target
+		}
+	}
+
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return tasks, nil
+}
+
+// sameTask compares tasks by their JSON form, which is what survives a trip
+// through any store and the journal file.
+func sameTask(a, b *Task) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
@@ -0,0 +1,178 @@
+package tasks
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func newTestJournal(t *testing.T) *Journal {
+	t.Helper()
+	return NewJournal(filepath.Join(t.TempDir(), "tasks.json.undo.json"))
+}
+
+func TestJournalUndoRedo(t *testing.T) {
+	t.Run("Undoes and redoes a delete", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "Keep"}, Task{ID: 2, Description: "Oops"}, Task{ID: 3})
+		journal := newTestJournal(t)
+
+		if _, err := DeleteTask(journal.Wrap(store, "delete 2"), 2, ChildrenRefuse); err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+
+		entry, err := journal.Undo(store)
+		if err != nil {
+			t.Fatalf("Undo returned error: %v", err)
+		}
+		if entry.Command != "delete 2" {
+			t.Errorf("Expected command %q, got %q", "delete 2", entry.Command)
+		}
+
+		tasks, _ := store.Load()
+		if !equalIDs(liveIDs(tasks), []int{1, 2, 3}) || tasks[1].Description != "Oops" {
+			t.Fatalf("Expected task 2 restored in place, got %+v", tasks)
+		}
+
+		if _, err := journal.Redo(store); err != nil {
+			t.Fatalf("Redo returned error: %v", err)
+		}
+
+		tasks, _ = store.Load()
+		if !equalIDs(liveIDs(tasks), []int{1, 3}) {
+			t.Errorf("Expected task 2 deleted again, got %v", liveIDs(tasks))
+		}
+	})
+
+	t.Run("Undoes an add and a status change in order", func(t *testing.T) {
+		store := NewMemoryStore()
+		journal := newTestJournal(t)
+
+		if _, err := AddTask(journal.Wrap(store, "add x"), "x", AddOptions{}); err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+		if _, err := MarkTaskDone(journal.Wrap(store, "mark-done 1"), 1, ChildrenRefuse); err != nil {
+			t.Fatalf("MarkTaskDone returned error: %v", err)
+		}
+
+		undo, _, err := journal.Entries()
+		if err != nil {
+			t.Fatalf("Entries returned error: %v", err)
+		}
+		if len(undo) != 2 || undo[0].Command != "mark-done 1" {
+			t.Fatalf("Expected 2 entries, newest first, got %+v", undo)
+		}
+
+		journal.Undo(store)
+		task, _ := store.Get(1)
+		if task.Status != "todo" || len(task.History) != 1 {
+			t.Errorf("Expected status todo with creation event only, got %+v", task)
+		}
+
+		journal.Undo(store)
+		if tasks, _ := store.Load(); len(tasks) != 0 {
+			t.Errorf("Expected no tasks, got %+v", tasks)
+		}
+
+		if _, err := journal.Undo(store); !errors.Is(err, ErrNothingToUndo) {
+			t.Errorf("Expected ErrNothingToUndo, got %v", err)
+		}
+	})
+
+	t.Run("Refuses to undo over later changes", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"})
+		journal := newTestJournal(t)
+
+		if _, err := MarkTaskDone(journal.Wrap(store, "mark-done 1"), 1, ChildrenRefuse); err != nil {
+			t.Fatalf("MarkTaskDone returned error: %v", err)
+		}
+		if _, err := UpdateTask(store, 1, describe("changed elsewhere")); err != nil {
+			t.Fatalf("UpdateTask returned error: %v", err)
+		}
+
+		if _, err := journal.Undo(store); !errors.Is(err, ErrConflict) {
+			t.Fatalf("Expected ErrConflict, got %v", err)
+		}
+
+		if undo, _, _ := journal.Entries(); len(undo) != 1 {
+			t.Errorf("Expected entry to stay undoable, got %d entries", len(undo))
+		}
+	})
+
+	t.Run("New mutations clear redo and the journal stays bounded", func(t *testing.T) {
+		store := NewMemoryStore()
+		journal := newTestJournal(t)
+		journal.Limit = 3
+
+		for range 5 {
+			if _, err := AddTask(journal.Wrap(store, "add"), "task", AddOptions{}); err != nil {
+				t.Fatalf("AddTask returned error: %v", err)
+			}
+		}
+
+		journal.Undo(store)
+		if _, err := AddTask(journal.Wrap(store, "add"), "task", AddOptions{}); err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+
+		undo, redo, err := journal.Entries()
+		if err != nil {
+			t.Fatalf("Entries returned error: %v", err)
+		}
+		if len(undo) != 3 || len(redo) != 0 {
+			t.Errorf("Expected 3 undo and 0 redo entries, got %d and %d", len(undo), len(redo))
+		}
+	})
+
+	t.Run("Restores slices changed in place", func(t *testing.T) {
+		// Spare capacity lets AddDependencies append and sort in the
+		// task's own backing array.
+		dependsOn := make([]int, 3, 8)
+		copy(dependsOn, []int{3, 5, 7})
+		store := NewMemoryStore(Task{ID: 1, DependsOn: dependsOn}, Task{ID: 3}, Task{ID: 4}, Task{ID: 5}, Task{ID: 7})
+		journal := newTestJournal(t)
+
+		if _, err := AddDependencies(journal.Wrap(store, "depend 1 --on 4"), 1, []int{4}); err != nil {
+			t.Fatalf("AddDependencies returned error: %v", err)
+		}
+		if _, err := journal.Undo(store); err != nil {
+			t.Fatalf("Undo returned error: %v", err)
+		}
+
+		task, _ := store.Get(1)
+		if !equalIDs(task.DependsOn, []int{3, 5, 7}) {
+			t.Errorf("Expected dependencies [3 5 7], got %v", task.DependsOn)
+		}
+	})
+
+	t.Run("Failed mutations are not recorded", func(t *testing.T) {
+		store := NewMemoryStore()
+		journal := newTestJournal(t)
+
+		if _, err := DeleteTask(journal.Wrap(store, "delete 9"), 9, ChildrenRefuse); !errors.Is(err, ErrTaskNotFound) {
+			t.Fatalf("Expected ErrTaskNotFound, got %v", err)
+		}
+
+		if undo, _, _ := journal.Entries(); len(undo) != 0 {
+			t.Errorf("Expected empty journal, got %+v", undo)
+		}
+	})
+}
+
+func TestJournalWithJSONStore(t *testing.T) {
+	filename := createTempTasksFile(t, []Task{{ID: 1, Description: "Buy milk", Status: "todo"}})
+	store := NewJSONStore(filename)
+	journal := NewJournal(JournalFile(filename))
+
+	if _, err := MarkTaskInProgress(journal.Wrap(store, "mark-in-progress 1"), 1, false); err != nil {
+		t.Fatalf("MarkTaskInProgress returned error: %v", err)
+	}
+
+	if _, err := journal.Undo(store); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+
+	task, _ := store.Get(1)
+	if task.Status != "todo" {
+		t.Errorf("Expected status todo after undo, got %q", task.Status)
+	}
+}
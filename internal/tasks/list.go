@@ -0,0 +1,165 @@
+package tasks
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+type Filter struct {
+	Status string
+	// Priority matches tasks with exactly this priority, or with at least
+	// this priority when PriorityAtLeast is set. PriorityNone matches all.
+	Priority        Priority
+	PriorityAtLeast bool
+	Overdue         bool
+	// DueBefore keeps only tasks with a due date before this time.
+	DueBefore time.Time
+	// Tags must all be present on a task and ExcludeTags must all be absent.
+	Tags        []string
+	ExcludeTags []string
+	// Project matches the project and all of its sub-projects.
+	Project string
+	// Ready keeps only unfinished tasks whose dependencies are all done.
+	Ready bool
+	// Query, when set, must also match.
+	Query Matcher
+	// Workflow decides which statuses count as finished and how statuses
+	// sort. The zero Workflow treats only "done" as finished.
+	Workflow Workflow
+}
+
+// Matcher selects tasks by criteria that may look at the other tasks, such
+// as whether a task is blocked.
+type Matcher interface {
+	Match(task Task, all []Task) bool
+}
+
+type ListResult struct {
+	Tasks []Task
+	Total int
+	// Progress has subtask counts for listed tasks that have subtasks,
+	// counted over all tasks rather than just the listed ones.
+	Progress map[int]Progress
+	// Blocked maps listed tasks that wait on unfinished dependencies to
+	// the IDs of those dependencies.
+	Blocked map[int][]int
+}
+
+func ListTasks(store Store, filter Filter) (ListResult, error) {
+	tasks, err := store.Load()
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	filtered := []Task{}
+	blocked := map[int][]int{}
+	total := 0
+	for _, task := range tasks {
+		if task.IsDeleted() {
+			continue
+		}
+
+		total++
+		if !filter.selects(task, tasks) {
+			continue
+		}
+
+		if blocking := BlockedBy(filter.Workflow, tasks, task); len(blocking) > 0 {
+			blocked[task.ID] = blocking
+		}
+		filtered = append(filtered, task)
+	}
+
+	SortByUrgency(filter.Workflow, filtered)
+
+	progress := map[int]Progress{}
+	for ID, p := range SubtaskProgress(filter.Workflow, tasks) {
+		if _, err := indexOf(filtered, ID); err == nil {
+			progress[ID] = p
+		}
+	}
+
+	return ListResult{Tasks: filtered, Total: total, Progress: progress, Blocked: blocked}, nil
+}
+
+func (f Filter) Matches(task Task) bool {
+	if f.Status != "" && task.Status != f.Status {
+		return false
+	}
+
+	if f.Priority != PriorityNone {
+		if f.PriorityAtLeast && task.Priority < f.Priority {
+			return false
+		}
+		if !f.PriorityAtLeast && task.Priority != f.Priority {
+			return false
+		}
+	}
+
+	if f.Overdue && !task.IsOverdue(f.Workflow, time.Now()) {
+		return false
+	}
+
+	if !f.DueBefore.IsZero() && (task.DueAt.IsZero() || !task.DueAt.Before(f.DueBefore)) {
+		return false
+	}
+
+	if f.Project != "" && !InProject(task.Project, f.Project) {
+		return false
+	}
+
+	for _, tag := range f.Tags {
+		if !task.HasTag(tag) {
+			return false
+		}
+	}
+
+	for _, tag := range f.ExcludeTags {
+		if task.HasTag(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// selects is Matches plus the checks that need the other tasks.
+func (f Filter) selects(task Task, all []Task) bool {
+	if !f.Matches(task) {
+		return false
+	}
+
+	if f.Ready && (f.Workflow.IsClosed(task.Status) || len(BlockedBy(f.Workflow, all, task)) > 0) {
+		return false
+	}
+
+	return f.Query == nil || f.Query.Match(task, all)
+}
+
+// SortByUrgency orders open tasks furthest along the workflow first (in
+// progress before todo), then closed ones; within a status higher priority,
+// then earlier due date (tasks without one last), then lower ID win.
+func SortByUrgency(w Workflow, tasks []Task) {
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		return cmp.Or(
+			cmp.Compare(w.urgencyRank(a.Status), w.urgencyRank(b.Status)),
+			cmp.Compare(b.Priority, a.Priority),
+			compareDue(a.DueAt, b.DueAt),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+}
+
+func compareDue(a, b time.Time) int {
+	switch {
+	case a.IsZero() && b.IsZero():
+		return 0
+	case a.IsZero():
+		return 1
+	case b.IsZero():
+		return -1
+	default:
+		return a.Compare(b)
+	}
+}
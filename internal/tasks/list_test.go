@@ -0,0 +1,145 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func ids(tasks []Task) []int {
+	result := make([]int, len(tasks))
+	for i, task := range tasks {
+		result[i] = task.ID
+	}
+	return result
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSortByUrgency(t *testing.T) {
+	list := []Task{
+		{ID: 1, Status: "done", Priority: PriorityCritical},
+		{ID: 2, Status: "todo", Priority: PriorityLow},
+		{ID: 3, Status: "todo", Priority: PriorityHigh},
+		{ID: 4, Status: "in progress"},
+		{ID: 5, Status: "in progress", Priority: PriorityCritical},
+		{ID: 6, Status: "todo"},
+		{ID: 7, Status: "todo", Priority: PriorityHigh},
+	}
+
+	SortByUrgency(Workflow{}, list)
+
+	want := []int{5, 4, 3, 7, 2, 6, 1}
+	if got := ids(list); !equalIDs(got, want) {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+}
+
+func TestListTasksByPriority(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "todo", Priority: PriorityLow},
+		Task{ID: 2, Status: "todo", Priority: PriorityHigh},
+		Task{ID: 3, Status: "todo", Priority: PriorityCritical},
+		Task{ID: 4, Status: "todo"},
+	)
+
+	t.Run("Exact priority", func(t *testing.T) {
+		result, err := ListTasks(store, Filter{Priority: PriorityHigh})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+
+		if got := ids(result.Tasks); !equalIDs(got, []int{2}) {
+			t.Fatalf("got %v, want [2]", got)
+		}
+	})
+
+	t.Run("At least priority", func(t *testing.T) {
+		result, err := ListTasks(store, Filter{Priority: PriorityHigh, PriorityAtLeast: true})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+
+		if got := ids(result.Tasks); !equalIDs(got, []int{3, 2}) {
+			t.Fatalf("got %v, want [3 2]", got)
+		}
+	})
+}
+
+func TestListTasksByDueDate(t *testing.T) {
+	past := time.Now().Add(-48 * time.Hour)
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(30 * 24 * time.Hour)
+
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "todo", DueAt: past},
+		Task{ID: 2, Status: "done", DueAt: past},
+		Task{ID: 3, Status: "todo", DueAt: soon},
+		Task{ID: 4, Status: "todo", DueAt: later},
+		Task{ID: 5, Status: "todo"},
+	)
+
+	t.Run("Overdue skips done and undated tasks", func(t *testing.T) {
+		result, err := ListTasks(store, Filter{Overdue: true})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+
+		if got := ids(result.Tasks); !equalIDs(got, []int{1}) {
+			t.Fatalf("got %v, want [1]", got)
+		}
+	})
+
+	t.Run("Due before", func(t *testing.T) {
+		result, err := ListTasks(store, Filter{DueBefore: time.Now().Add(7 * 24 * time.Hour)})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+
+		if got := ids(result.Tasks); !equalIDs(got, []int{1, 3, 2}) {
+			t.Fatalf("got %v, want [1 3 2]", got)
+		}
+	})
+
+	t.Run("Earlier due date sorts first", func(t *testing.T) {
+		result, err := ListTasks(store, Filter{Status: "todo"})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+
+		if got := ids(result.Tasks); !equalIDs(got, []int{1, 3, 4, 5}) {
+			t.Fatalf("got %v, want [1 3 4 5]", got)
+		}
+	})
+}
+
+func TestListTasksByQuery(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "todo"},
+		Task{ID: 2, Status: "todo", DependsOn: []int{1}},
+		Task{ID: 3, Status: "done"},
+	)
+
+	blocked := matchFunc(func(task Task, all []Task) bool { return len(BlockedBy(Workflow{}, all, task)) > 0 })
+
+	result, err := ListTasks(store, Filter{Status: "todo", Query: blocked})
+	if err != nil {
+		t.Fatalf("ListTasks returned error: %v", err)
+	}
+
+	if got := ids(result.Tasks); !equalIDs(got, []int{2}) {
+		t.Fatalf("got %v, want [2]", got)
+	}
+	if result.Total != 3 {
+		t.Errorf("Expected a total of 3, got %d", result.Total)
+	}
+}
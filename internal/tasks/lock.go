@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockSuffix         = ".lock"
+	DefaultLockTimeout = 5 * time.Second
+	lockRetryInterval  = 50 * time.Millisecond
+)
+
+var ErrLocked = errors.New("task file is locked by another task-cli process")
+
+func LockFile(file string) string {
+	return file + lockSuffix
+}
+
+type fileLock struct {
+	f *os.File
+}
+
+// acquireLock takes an exclusive advisory lock on path, retrying until
+// timeout elapses. A zero timeout makes a single attempt.
+func acquireLock(path string, timeout time.Duration) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		if locked {
+			return &fileLock{f: f}, nil
+		}
+
+		if !time.Now().Before(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: gave up waiting for %s after %s", ErrLocked, path, timeout)
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (l *fileLock) release() error {
+	if err := unlock(l.f); err != nil {
+		l.f.Close()
+		return err
+	}
+
+	return l.f.Close()
+}
@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package tasks
+
+import "os"
+
+func tryLock(f *os.File) (bool, error) {
+	return true, nil
+}
+
+func unlock(f *os.File) error {
+	return nil
+}
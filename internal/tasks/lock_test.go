@@ -0,0 +1,94 @@
+package tasks
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	t.Run("Second lock times out while first is held", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json.lock")
+
+		lock, err := acquireLock(path, 0)
+		if err != nil {
+			t.Fatalf("acquireLock returned error: %v", err)
+		}
+		defer lock.release()
+
+		_, err = acquireLock(path, 100*time.Millisecond)
+		if !errors.Is(err, ErrLocked) {
+			t.Fatalf("Expected ErrLocked, got %v", err)
+		}
+	})
+
+	t.Run("Lock can be taken again after release", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json.lock")
+
+		lock, err := acquireLock(path, 0)
+		if err != nil {
+			t.Fatalf("acquireLock returned error: %v", err)
+		}
+
+		if err := lock.release(); err != nil {
+			t.Fatalf("release returned error: %v", err)
+		}
+
+		lock, err = acquireLock(path, 0)
+		if err != nil {
+			t.Fatalf("Expected lock to be free after release, got %v", err)
+		}
+		lock.release()
+	})
+}
+
+func TestJSONStoreConcurrentUpdates(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tasks.json")
+
+	const writers = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			store := NewJSONStore(filename)
+			errs <- store.Update(func(tasks []Task) ([]Task, error) {
+				newID := 1
+				if len(tasks) > 0 {
+					newID = tasks[len(tasks)-1].ID + 1
+				}
+				return append(tasks, Task{ID: newID, Description: "task"}), nil
+			})
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update returned error: %v", err)
+		}
+	}
+
+	tasks, err := Load(filename)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if len(tasks) != writers {
+		t.Fatalf("Expected %d tasks, got %d", writers, len(tasks))
+	}
+
+	for i, task := range tasks {
+		if task.ID != i+1 {
+			t.Errorf("task[%d].ID: got %d, want %d", i, task.ID, i+1)
+		}
+	}
+}
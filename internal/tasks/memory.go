@@ -0,0 +1,70 @@
+package tasks
+
+import "sync"
+
+type MemoryStore struct {
+	mu    sync.Mutex
+	tasks []Task
+	meta  Meta
+}
+
+func NewMemoryStore(tasks ...Task) *MemoryStore {
+	return &MemoryStore{tasks: append([]Task{}, tasks...)}
+}
+
+func (s *MemoryStore) Load() ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Task{}, s.tasks...), nil
+}
+
+func (s *MemoryStore) Save(tasks []Task) error {
+	return s.Update(func([]Task) ([]Task, error) {
+		return tasks, nil
+	})
+}
+
+func (s *MemoryStore) Get(ID int) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return findTask(s.tasks, ID)
+}
+
+func (s *MemoryStore) Update(fn func(tasks []Task) ([]Task, error)) error {
+	return s.UpdateMeta(func(tasks []Task, _ *Meta) ([]Task, error) {
+		return fn(tasks)
+	})
+}
+
+func (s *MemoryStore) LoadMeta() (Meta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta := s.meta
+	meta.Observe(s.tasks)
+	return meta, nil
+}
+
+func (s *MemoryStore) UpdateMeta(fn func(tasks []Task, meta *Meta) ([]Task, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta := s.meta
+	meta.Observe(s.tasks)
+
+	tasks, err := fn(append([]Task{}, s.tasks...), &meta)
+	if err != nil {
+		return err
+	}
+
+	if err := checkUniqueIDs(tasks); err != nil {
+		return err
+	}
+
+	meta.Observe(tasks)
+	s.tasks = append([]Task{}, tasks...)
+	s.meta = meta
+	return nil
+}
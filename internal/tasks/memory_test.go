@@ -0,0 +1,102 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Run("Service functions work against memory store", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "Buy groceries", Status: "todo"})
+
+		if _, err := AddTask(store, "Cook dinner", AddOptions{}); err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+
+		if _, err := MarkTaskDone(store, 1, ChildrenRefuse); err != nil {
+			t.Fatalf("MarkTaskDone returned error: %v", err)
+		}
+
+		tasks, err := store.Load()
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+
+		if len(tasks) != 2 {
+			t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+		}
+
+		if tasks[0].Status != "done" {
+			t.Errorf("Expected status %q, got %q", "done", tasks[0].Status)
+		}
+
+		if tasks[1].ID != 2 || tasks[1].Description != "Cook dinner" {
+			t.Errorf("unexpected task[1]: %+v", tasks[1])
+		}
+	})
+
+	t.Run("Load returns a copy", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "Buy groceries"})
+
+		tasks, _ := store.Load()
+		tasks[0].Description = "Changed"
+
+		task, err := store.Get(1)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+
+		if task.Description != "Buy groceries" {
+			t.Errorf("Expected stored task to be unchanged, got %q", task.Description)
+		}
+	})
+
+	t.Run("Get returns error when task not found", func(t *testing.T) {
+		_, err := NewMemoryStore().Get(1)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Fatalf("Expected error to mention %q, got %v", "not found", err)
+		}
+	})
+}
+
+func TestCopyTasks(t *testing.T) {
+	t.Run("Copies all tasks into empty store", func(t *testing.T) {
+		src := NewMemoryStore(
+			Task{ID: 1, Description: "Buy groceries", Status: "todo"},
+			Task{ID: 3, Description: "Cook dinner", Status: "done"},
+		)
+		dst := NewMemoryStore()
+
+		n, err := CopyTasks(dst, src)
+		if err != nil {
+			t.Fatalf("CopyTasks returned error: %v", err)
+		}
+
+		if n != 2 {
+			t.Fatalf("Expected 2 copied tasks, got %d", n)
+		}
+
+		task, err := dst.Get(3)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+
+		if task.Description != "Cook dinner" || task.Status != "done" {
+			t.Errorf("unexpected copied task: %+v", task)
+		}
+	})
+
+	t.Run("Refuses to overwrite non-empty store", func(t *testing.T) {
+		src := NewMemoryStore(Task{ID: 1, Description: "Buy groceries"})
+		dst := NewMemoryStore(Task{ID: 1, Description: "Existing"})
+
+		if _, err := CopyTasks(dst, src); err == nil {
+			t.Fatal("Expected error when destination is not empty, got nil")
+		}
+
+		task, _ := dst.Get(1)
+		if task.Description != "Existing" {
+			t.Errorf("Destination should be unchanged, got %q", task.Description)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package tasks
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// legacyMetaSuffix names the file the ID counter was kept in before task
+// files had an envelope.
+const legacyMetaSuffix = ".meta.json"
+
+// Meta is store-wide data kept alongside the tasks.
+type Meta struct {
+	// NextID is the ID the next new task gets. It only ever grows, so the
+	// IDs of deleted and purged tasks are never handed out again.
+	NextID int `json:"next_id"`
+	// Views are the saved list views by name.
+	Views map[string]View `json:"views,omitempty"`
+}
+
+// Observe advances NextID past every ID in tasks.
+func (m *Meta) Observe(tasks []Task) {
+	m.NextID = max(m.NextID, 1)
+	for _, task := range tasks {
+		m.NextID = max(m.NextID, task.ID+1)
+	}
+}
+
+func loadLegacyMeta(file string) (Meta, error) {
+	var meta Meta
+
+	metaFile := file + legacyMetaSuffix
+	data, err := os.ReadFile(metaFile)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(data) == 0) {
+		return meta, nil
+	}
+	if err != nil {
+		return meta, err
+	}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, corruptJSON(metaFile, err)
+	}
+
+	return meta, nil
+}
+
+// removeLegacyMeta deletes the old counter file once its contents live in
+// the task file.
+func removeLegacyMeta(file string) error {
+	metaFile := file + legacyMetaSuffix
+	for _, name := range []string{metaFile, BackupFile(metaFile)} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkUniqueIDs reports the first ID that appears twice in tasks.
+func checkUniqueIDs(tasks []Task) error {
+	seen := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		if seen[task.ID] {
+			return fmt.Errorf("duplicate task ID %d", task.ID)
+		}
+		seen[task.ID] = true
+	}
+
+	return nil
+}
+
+// newUID returns a random, time-ordered UUID (version 7).
+func newUID(now time.Time) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixMilli()))
+	copy(b[:6], ms[2:])
+
+	b[6] = b[6]&0x0f | 0x70
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,101 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestTaskIDsAreNotReused(t *testing.T) {
+	t.Run("After deleting and purging the newest task", func(t *testing.T) {
+		store := NewJSONStore(createTempTasksFile(t, []Task{{ID: 1}, {ID: 2}}))
+
+		if _, err := DeleteTask(store, 2, ChildrenRefuse); err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+		if _, err := PurgeTasks(store, 0, time.Now()); err != nil {
+			t.Fatalf("PurgeTasks returned error: %v", err)
+		}
+
+		task, err := AddTask(store, "next", AddOptions{})
+		if err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+		if task.ID != 3 {
+			t.Errorf("Expected ID 3, got %d", task.ID)
+		}
+	})
+
+	t.Run("After undoing an add", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1})
+		journal := newTestJournal(t)
+
+		if _, err := AddTask(journal.Wrap(store, "add"), "oops", AddOptions{}); err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+		if _, err := journal.Undo(store); err != nil {
+			t.Fatalf("Undo returned error: %v", err)
+		}
+
+		task, err := AddTask(store, "again", AddOptions{})
+		if err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+		if task.ID != 3 {
+			t.Errorf("Expected ID 3, got %d", task.ID)
+		}
+	})
+
+	t.Run("Regardless of task order in the file", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 7}, Task{ID: 2})
+
+		task, err := AddTask(store, "next", AddOptions{})
+		if err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+		if task.ID != 8 {
+			t.Errorf("Expected ID 8, got %d", task.ID)
+		}
+	})
+}
+
+func TestLoadDuplicateIDs(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(filename, []byte(`[{"id": 1}, {"id": 2}, {"id": 1}]`), 0644); err != nil {
+		t.Fatalf("failed to write tasks file: %v", err)
+	}
+
+	_, err := Load(filename)
+	if !errors.Is(err, ErrCorruptStore) {
+		t.Fatalf("Expected ErrCorruptStore, got %v", err)
+	}
+
+	if err := NewJSONStore(filename).Save([]Task{{ID: 4}, {ID: 4}}); err == nil {
+		t.Error("Expected Save to refuse duplicate IDs")
+	}
+}
+
+func TestAddTaskWithUID(t *testing.T) {
+	store := NewMemoryStore()
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, err := AddTask(store, "first", AddOptions{WithUID: true})
+	if err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+	second, _ := AddTask(store, "second", AddOptions{WithUID: true})
+	plain, _ := AddTask(store, "plain", AddOptions{})
+
+	if !uuid.MatchString(first.UID) {
+		t.Errorf("Expected a version 7 UUID, got %q", first.UID)
+	}
+	if first.UID == second.UID {
+		t.Errorf("Expected distinct UIDs, got %q twice", first.UID)
+	}
+	if plain.UID != "" {
+		t.Errorf("Expected no UID by default, got %q", plain.UID)
+	}
+}
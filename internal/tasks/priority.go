@@ -0,0 +1,60 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityCritical
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "critical"}
+
+func ParsePriority(value string) (Priority, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	for i, name := range priorityNames {
+		if value == name {
+			return Priority(i), nil
+		}
+	}
+
+	if n, err := strconv.Atoi(value); err == nil && n >= int(PriorityNone) && n <= int(PriorityCritical) {
+		return Priority(n), nil
+	}
+
+	return PriorityNone, &ValidationError{
+		Field:   "priority",
+		Message: fmt.Sprintf("invalid priority %q (allowed: %s or 0-4)", value, strings.Join(priorityNames, ", ")),
+	}
+}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityCritical {
+		return strconv.Itoa(int(p))
+	}
+
+	return priorityNames[p]
+}
+
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(text []byte) error {
+	parsed, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+	return nil
+}
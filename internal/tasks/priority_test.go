@@ -0,0 +1,73 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParsePriority(t *testing.T) {
+	cases := []struct {
+		input string
+		want  Priority
+	}{
+		{"low", PriorityLow},
+		{"Medium", PriorityMedium},
+		{" HIGH ", PriorityHigh},
+		{"critical", PriorityCritical},
+		{"none", PriorityNone},
+		{"0", PriorityNone},
+		{"4", PriorityCritical},
+	}
+
+	for _, c := range cases {
+		got, err := ParsePriority(c.input)
+		if err != nil {
+			t.Errorf("ParsePriority(%q) returned error: %v", c.input, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParsePriority(%q): got %v, want %v", c.input, got, c.want)
+		}
+	}
+
+	for _, input := range []string{"urgent", "5", "-1", ""} {
+		if _, err := ParsePriority(input); !errors.Is(err, ErrValidation) {
+			t.Errorf("ParsePriority(%q): expected validation error, got %v", input, err)
+		}
+	}
+}
+
+func TestPriorityJSON(t *testing.T) {
+	t.Run("Round-trips as name", func(t *testing.T) {
+		data, err := json.Marshal(Task{ID: 1, Priority: PriorityHigh})
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+
+		var task Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			t.Fatalf("Unmarshal returned error: %v", err)
+		}
+
+		if task.Priority != PriorityHigh {
+			t.Errorf("got %v, want %v (json: %s)", task.Priority, PriorityHigh, data)
+		}
+	})
+
+	t.Run("Omitted when unset", func(t *testing.T) {
+		data, err := json.Marshal(Task{ID: 1})
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("Unmarshal returned error: %v", err)
+		}
+
+		if _, ok := fields["priority"]; ok {
+			t.Errorf("expected no priority field, got %s", data)
+		}
+	})
+}
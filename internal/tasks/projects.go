@@ -0,0 +1,88 @@
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var projectSegmentPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+type ProjectSummary struct {
+	Name string `json:"name"`
+	// Counts holds the number of tasks per status in this project and all
+	// of its sub-projects.
+	Counts map[string]int `json:"counts"`
+	Total  int            `json:"total"`
+}
+
+// NormalizeProject lower-cases a dotted project path such as "infra.ci.flaky"
+// and checks that every segment is non-empty.
+func NormalizeProject(project string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(project))
+
+	for _, segment := range strings.Split(normalized, ".") {
+		if !projectSegmentPattern.MatchString(segment) {
+			return "", &ValidationError{Field: "project", Message: fmt.Sprintf("invalid project %q", project)}
+		}
+	}
+
+	return normalized, nil
+}
+
+// InProject reports whether project is parent or one of its sub-projects.
+func InProject(project, parent string) bool {
+	return project == parent || strings.HasPrefix(project, parent+".")
+}
+
+// projectAncestors returns "a", "a.b", "a.b.c" for "a.b.c".
+func projectAncestors(project string) []string {
+	if project == "" {
+		return nil
+	}
+
+	segments := strings.Split(project, ".")
+	ancestors := make([]string, len(segments))
+	for i := range segments {
+		ancestors[i] = strings.Join(segments[:i+1], ".")
+	}
+
+	return ancestors
+}
+
+func Projects(store Store) ([]ProjectSummary, error) {
+	tasks, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := map[string]*ProjectSummary{}
+	for _, task := range tasks {
+		if task.IsDeleted() {
+			continue
+		}
+
+		for _, name := range projectAncestors(task.Project) {
+			summary, ok := summaries[name]
+			if !ok {
+				summary = &ProjectSummary{Name: name, Counts: map[string]int{}}
+				summaries[name] = summary
+			}
+
+			summary.Counts[task.Status]++
+			summary.Total++
+		}
+	}
+
+	result := make([]ProjectSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+
+	slices.SortFunc(result, func(a, b ProjectSummary) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
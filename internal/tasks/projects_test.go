@@ -0,0 +1,146 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeProject(t *testing.T) {
+	for input, want := range map[string]string{
+		"infra":          "infra",
+		"Infra.CI.flaky": "infra.ci.flaky",
+		" web_app-2 ":    "web_app-2",
+	} {
+		got, err := NormalizeProject(input)
+		if err != nil {
+			t.Errorf("NormalizeProject(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeProject(%q): got %q, want %q", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", ".", "infra.", ".infra", "infra..ci", "two words"} {
+		if _, err := NormalizeProject(input); !errors.Is(err, ErrValidation) {
+			t.Errorf("NormalizeProject(%q): expected validation error, got %v", input, err)
+		}
+	}
+}
+
+func TestInProject(t *testing.T) {
+	cases := []struct {
+		project, parent string
+		want            bool
+	}{
+		{"infra", "infra", true},
+		{"infra.ci", "infra", true},
+		{"infra.ci.flaky", "infra.ci", true},
+		{"infrastructure", "infra", false},
+		{"infra", "infra.ci", false},
+		{"", "infra", false},
+	}
+
+	for _, c := range cases {
+		if got := InProject(c.project, c.parent); got != c.want {
+			t.Errorf("InProject(%q, %q): got %v, want %v", c.project, c.parent, got, c.want)
+		}
+	}
+}
+
+func TestListTasksByProject(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "todo", Project: "infra"},
+		Task{ID: 2, Status: "todo", Project: "infra.ci.flaky"},
+		Task{ID: 3, Status: "todo", Project: "infrastructure"},
+		Task{ID: 4, Status: "todo"},
+	)
+
+	result, err := ListTasks(store, Filter{Project: "infra"})
+	if err != nil {
+		t.Fatalf("ListTasks returned error: %v", err)
+	}
+
+	if got := ids(result.Tasks); !equalIDs(got, []int{1, 2}) {
+		t.Fatalf("got %v, want [1 2]", got)
+	}
+}
+
+func TestAddTaskWithProject(t *testing.T) {
+	store := NewMemoryStore()
+
+	task, err := AddTask(store, "Fix flaky test", AddOptions{Project: "Infra.CI"})
+	if err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+
+	if task.Project != "infra.ci" {
+		t.Errorf("Expected project %q, got %q", "infra.ci", task.Project)
+	}
+
+	if _, err := AddTask(store, "Bad", AddOptions{Project: "infra..ci"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected validation error for invalid project, got %v", err)
+	}
+}
+
+func TestUpdateTaskProject(t *testing.T) {
+	store := NewMemoryStore(Task{ID: 1, Description: "Fix flaky test", Project: "infra"})
+
+	project := "Infra.CI"
+	task, err := UpdateTask(store, 1, TaskChanges{Project: &project})
+	if err != nil {
+		t.Fatalf("UpdateTask returned error: %v", err)
+	}
+	if task.Project != "infra.ci" {
+		t.Fatalf("Expected project %q, got %q", "infra.ci", task.Project)
+	}
+
+	none := ""
+	task, err = UpdateTask(store, 1, TaskChanges{Project: &none})
+	if err != nil {
+		t.Fatalf("UpdateTask returned error: %v", err)
+	}
+	if task.Project != "" {
+		t.Fatalf("Expected project to be cleared, got %q", task.Project)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "todo", Project: "infra"},
+		Task{ID: 2, Status: "done", Project: "infra.ci"},
+		Task{ID: 3, Status: "in progress", Project: "infra.ci.flaky"},
+		Task{ID: 4, Status: "todo", Project: "web"},
+		Task{ID: 5, Status: "todo"},
+	)
+
+	summaries, err := Projects(store)
+	if err != nil {
+		t.Fatalf("Projects returned error: %v", err)
+	}
+
+	var names []string
+	for _, s := range summaries {
+		names = append(names, s.Name)
+	}
+
+	want := []string{"infra", "infra.ci", "infra.ci.flaky", "web"}
+	if len(names) != len(want) {
+		t.Fatalf("got projects %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("got projects %v, want %v", names, want)
+		}
+	}
+
+	infra := summaries[0]
+	if infra.Total != 3 || infra.Counts["todo"] != 1 || infra.Counts["done"] != 1 || infra.Counts["in progress"] != 1 {
+		t.Errorf("unexpected infra summary: %+v", infra)
+	}
+
+	ci := summaries[1]
+	if ci.Total != 2 || ci.Counts["todo"] != 0 {
+		t.Errorf("unexpected infra.ci summary: %+v", ci)
+	}
+}
@@ -1,177 +1,341 @@
 package tasks
 
 import (
-	"errors"
 	"fmt"
+	"slices"
 	"time"
 )
 
-func AddTask(file, description string) error {
-	if description == "" {
-		return errors.New("task description is required")
-	}
+type AddOptions struct {
+	Priority Priority
+	DueAt    time.Time
+	Tags     []string
+	Project  string
+	ParentID int
+	// Status of the new task; empty means the workflow's initial status.
+	Status string
+	// Workflow the status must belong to. The zero Workflow means the
+	// default one.
+	Workflow Workflow
+	// WithUID also gives the task a globally unique ID (a UUID) that stays
+	// meaningful outside this task list.
+	WithUID bool
+}
+
+// TaskChanges lists the fields UpdateTask should change; nil fields are left
+// alone. A zero DueAt or an empty Project clears that field.
+type TaskChanges struct {
+	Description *string
+	DueAt       *time.Time
+	Project     *string
+}
 
-	if file == "" {
-		return errors.New("filename cannot be empty")
+func AddTask(store Store, description string, opts AddOptions) (Task, error) {
+	if description == "" {
+		return Task{}, errDescriptionRequired()
 	}
 
-	tasks, err := Load(file)
+	tags, err := normalizeTags(opts.Tags)
 	if err != nil {
-		return err
+		return Task{}, err
 	}
 
-	newID := 1
-	if len(tasks) > 0 {
-		newID = tasks[len(tasks)-1].ID + 1
+	project := opts.Project
+	if project != "" {
+		if project, err = NormalizeProject(project); err != nil {
+			return Task{}, err
+		}
 	}
 
-	newTask := Task{ID: newID, Description: description, Status: "todo", CreatedAt: time.Now()}
-	tasks = append(tasks, newTask)
-
-	err = Save(file, tasks)
-	if err != nil {
-		return err
+	workflow := opts.Workflow
+	if len(workflow.States) == 0 {
+		workflow = DefaultWorkflow()
 	}
-	fmt.Printf("Task added successfully (ID: %d)\n", newTask.ID)
-	return nil
-}
 
-func ListTasks(file string, status string) error {
-	if file == "" {
-		return errors.New("filename cannot be empty")
+	status := opts.Status
+	if status == "" {
+		status = workflow.Initial
 	}
-
-	tasks, err := Load(file)
-	if err != nil {
-		return err
+	if err := workflow.checkState(status); err != nil {
+		return Task{}, err
 	}
 
-	if len(tasks) == 0 {
-		fmt.Println("No tasks found.")
-		return nil
+	now := time.Now()
+
+	var uid string
+	if opts.WithUID {
+		if uid, err = newUID(now); err != nil {
+			return Task{}, err
+		}
 	}
 
-	filtered := tasks
-	if status != "" {
-		filtered = nil
-		for _, task := range tasks {
-			if task.Status == status {
-				filtered = append(filtered, task)
+	var newTask Task
+	err = store.UpdateMeta(func(tasks []Task, meta *Meta) ([]Task, error) {
+		if opts.ParentID != 0 {
+			if _, err := indexOf(tasks, opts.ParentID); err != nil {
+				return nil, err
 			}
 		}
 
-		if len(filtered) == 0 {
-			fmt.Printf("No tasks with status %q found.\n", status)
-			return nil
+		newID := meta.NextID
+		meta.NextID++
+
+		newTask = Task{
+			ID:          newID,
+			UID:         uid,
+			Description: description,
+			Status:      status,
+			Priority:    opts.Priority,
+			CreatedAt:   now,
+			DueAt:       opts.DueAt,
+			Tags:        mergeTags(nil, tags, nil),
+			Project:     project,
+			ParentID:    opts.ParentID,
 		}
+		newTask.record(now, "status", "", status)
+		return append(tasks, newTask), nil
+	})
+	if err != nil {
+		return Task{}, err
 	}
 
-	fmt.Printf("%-4s %-12s %-17s %s\n", "ID", "Status", "Created", "Description")
-
-	for _, task := range filtered {
-		fmt.Printf(
-			"%-4d %-12s %-17s %s\n",
-			task.ID,
-			task.Status,
-			task.CreatedAt.Format("2006-01-02 15:04"),
-			task.Description,
-		)
-	}
-
-	return nil
+	return newTask, nil
 }
 
-func UpdateTask(file string, ID int, description string) error {
-	if file == "" {
-		return errors.New("filename cannot be empty")
+func UpdateTask(store Store, ID int, changes TaskChanges) (Task, error) {
+	if changes == (TaskChanges{}) {
+		return Task{}, &ValidationError{Field: "changes", Message: "nothing to update"}
 	}
 
-	if description == "" {
-		return errors.New("task description is required")
+	if changes.Description != nil && *changes.Description == "" {
+		return Task{}, errDescriptionRequired()
 	}
 
-	tasks, err := Load(file)
-	if err != nil {
-		return err
+	if changes.Project != nil && *changes.Project != "" {
+		project, err := NormalizeProject(*changes.Project)
+		if err != nil {
+			return Task{}, err
+		}
+		changes.Project = &project
 	}
 
-	for i := range tasks {
-		if tasks[i].ID == ID {
-			tasks[i].Description = description
-			tasks[i].UpdatedAt = time.Now()
+	return updateTask(store, ID, func(task *Task) {
+		now := time.Now()
+		if changes.Description != nil && *changes.Description != task.Description {
+			task.record(now, "description", task.Description, *changes.Description)
+			task.Description = *changes.Description
+		}
+		if changes.DueAt != nil {
+			task.DueAt = *changes.DueAt
+		}
+		if changes.Project != nil {
+			task.Project = *changes.Project
+		}
+		task.UpdatedAt = now
+	})
+}
+
+func SetPriority(store Store, ID int, priority Priority) (Task, error) {
+	return updateTask(store, ID, func(task *Task) {
+		task.Priority = priority
+		task.UpdatedAt = time.Now()
+	})
+}
 
-			err = Save(file, tasks)
-			if err != nil {
-				return err
-			}
+type StatusOptions struct {
+	// Children decides what happens to unfinished subtasks when a task is
+	// marked done.
+	Children ChildPolicy
+	// Force starts a task even if it is blocked by unfinished dependencies.
+	Force bool
+	// Now is the time of the change; zero means the current time.
+	Now time.Time
+}
+
+// SetStatus moves a task to status if workflow allows it. Starting a blocked
+// task ("in progress") is refused unless opts.Force is set, and closing a
+// task handles its unfinished subtasks according to opts.Children.
+func SetStatus(store Store, workflow Workflow, ID int, status string, opts StatusOptions) (Task, error) {
+	var updated Task
+	err := store.Update(func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := workflow.checkTransition(tasks[i], status); err != nil {
+			return nil, err
+		}
 
-			fmt.Printf("Task updated successfully (ID: %d)\n", ID)
-			return nil
+		now := opts.Now
+		if now.IsZero() {
+			now = time.Now()
 		}
+		switch {
+		case status == "in progress":
+			if blocking := BlockedBy(workflow, tasks, tasks[i]); len(blocking) > 0 && !opts.Force {
+				return nil, &ConflictError{
+					ID:      ID,
+					Message: fmt.Sprintf("task %d is blocked by unfinished tasks %s", ID, JoinIDs(blocking, ", ")),
+				}
+			}
+		case workflow.IsClosed(status):
+			if err := finishSubtasks(tasks, workflow, i, status, opts.Children, now); err != nil {
+				return nil, err
+			}
+		}
+
+		tasks[i].setStatus(status, now)
+		updated = tasks[i]
+		return tasks, nil
+	})
+	if err != nil {
+		return Task{}, err
 	}
 
-	return fmt.Errorf("task with ID %d not found", ID)
+	return updated, nil
 }
 
-func MarkTaskInProgress(file string, ID int) error {
-	return markTaskStatus(file, ID, "in progress")
+// MarkTaskInProgress starts a task under the default workflow.
+func MarkTaskInProgress(store Store, ID int, force bool) (Task, error) {
+	return SetStatus(store, DefaultWorkflow(), ID, "in progress", StatusOptions{Force: force})
 }
 
-func MarkTaskDone(file string, ID int) error {
-	return markTaskStatus(file, ID, "done")
-
+// MarkTaskDone finishes a task under the default workflow.
+func MarkTaskDone(store Store, ID int, policy ChildPolicy) (Task, error) {
+	return SetStatus(store, DefaultWorkflow(), ID, "done", StatusOptions{Children: policy})
 }
 
-func markTaskStatus(file string, ID int, status string) error {
-	if file == "" {
-		return errors.New("filename cannot be empty")
-	}
+// finishSubtasks applies policy to the unfinished subtasks of tasks[i]
+// before it moves to the closed status; cascading closes them the same way.
+func finishSubtasks(tasks []Task, workflow Workflow, i int, status string, policy ChildPolicy, now time.Time) error {
+	ID := tasks[i].ID
 
-	tasks, err := Load(file)
-	if err != nil {
-		return err
+	var open []int
+	for _, childID := range descendants(tasks, ID) {
+		if c, _ := indexOf(tasks, childID); !workflow.IsClosed(tasks[c].Status) {
+			open = append(open, childID)
+		}
 	}
 
-	for i := range tasks {
-		if tasks[i].ID == ID {
-			tasks[i].Status = status
+	if len(open) == 0 {
+		return nil
+	}
 
-			err = Save(file, tasks)
-			if err != nil {
+	switch policy {
+	case ChildrenCascade:
+		for _, childID := range open {
+			c, _ := indexOf(tasks, childID)
+			if err := workflow.checkTransition(tasks[c], status); err != nil {
 				return err
 			}
-
-			fmt.Printf("Task updated successfully (ID: %d)\n", ID)
-			return nil
+			tasks[c].setStatus(status, now)
+		}
+	case ChildrenReparent:
+		// Move up every unfinished subtree, including ones hanging off a
+		// subtask that is already done.
+		for _, childID := range open {
+			c, _ := indexOf(tasks, childID)
+			if !slices.Contains(open, tasks[c].ParentID) {
+				tasks[c].ParentID = tasks[i].ParentID
+			}
+		}
+	default:
+		return &ConflictError{
+			ID:      ID,
+			Message: fmt.Sprintf("task %d has %d unfinished subtasks", ID, len(open)),
 		}
 	}
 
-	return fmt.Errorf("task with ID %d not found", ID)
+	return nil
+}
+
+// DeleteTask moves a task and, with ChildrenCascade, all of its subtasks to
+// the trash. The requested task is first in the returned slice.
+func DeleteTask(store Store, ID int, policy ChildPolicy) ([]Task, error) {
+	return DeleteTaskAt(store, ID, policy, time.Now())
 }
 
-func DeleteTask(file string, ID int) error {
-	if file == "" {
-		return errors.New("filename cannot be empty")
+// DeleteTaskAt is DeleteTask with the time of deletion given. Tasks deleted
+// at the same time are restored together.
+func DeleteTaskAt(store Store, ID int, policy ChildPolicy, now time.Time) ([]Task, error) {
+	var deleted []Task
+	err := store.Update(func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, ID)
+		if err != nil {
+			return nil, err
+		}
+
+		removed := []int{ID}
+		if children := descendants(tasks, ID); len(children) > 0 {
+			switch policy {
+			case ChildrenCascade:
+				removed = append(removed, children...)
+			case ChildrenReparent:
+				for c := range tasks {
+					if tasks[c].ParentID == ID && !tasks[c].IsDeleted() {
+						tasks[c].ParentID = tasks[i].ParentID
+					}
+				}
+			default:
+				return nil, &ConflictError{
+					ID:      ID,
+					Message: fmt.Sprintf("task %d has %d subtasks", ID, len(children)),
+				}
+			}
+		}
+
+		for _, removedID := range removed {
+			r, _ := indexOf(tasks, removedID)
+			tasks[r].DeletedAt = now
+			deleted = append(deleted, tasks[r])
+		}
+
+		return tasks, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	tasks, err := Load(file)
+	return deleted, nil
+}
+
+func updateTask(store Store, ID int, change func(task *Task)) (Task, error) {
+	var updated Task
+	err := store.Update(func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, ID)
+		if err != nil {
+			return nil, err
+		}
+
+		change(&tasks[i])
+		updated = tasks[i]
+		return tasks, nil
+	})
 	if err != nil {
-		return err
+		return Task{}, err
 	}
 
-	for i := range tasks {
-		if tasks[i].ID == ID {
-			tasks = append(tasks[:i], tasks[i+1:]...)
+	return updated, nil
+}
 
-			err = Save(file, tasks)
-			if err != nil {
-				return err
-			}
+func errDescriptionRequired() error {
+	return &ValidationError{Field: "description", Message: "task description is required"}
+}
 
-			fmt.Printf("Task deleted successfully (ID: %d)\n", ID)
-			return nil
-		}
+// indexOf finds a task that is not in the trash.
+func indexOf(tasks []Task, ID int) (int, error) {
+	if i := indexOfAny(tasks, ID); i >= 0 && !tasks[i].IsDeleted() {
+		return i, nil
 	}
 
-	return fmt.Errorf("task with ID %d not found", ID)
+	return -1, &TaskNotFoundError{ID: ID}
+}
+
+// indexOfAny finds a task whether or not it is in the trash, returning -1
+// if there is none.
+func indexOfAny(tasks []Task, ID int) int {
+	return slices.IndexFunc(tasks, func(task Task) bool {
+		return task.ID == ID
+	})
 }
@@ -1,7 +1,7 @@
 package tasks
 
 import (
-	"io"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,14 +14,54 @@ func TestAddTask(t *testing.T) {
 		dir := t.TempDir()
 		filename := filepath.Join(dir, "tasks.json")
 
-		err := AddTask(filename, "Buy groceries")
+		task, err := AddTask(NewJSONStore(filename), "Buy groceries", AddOptions{})
 		if err != nil {
-			t.Errorf("Failed to add task: %v", err)
+			t.Fatalf("Failed to add task: %v", err)
+		}
+
+		if task.ID != 1 || task.Description != "Buy groceries" || task.Status != "todo" {
+			t.Errorf("unexpected task: %+v", task)
+		}
+
+		if task.CreatedAt.IsZero() {
+			t.Errorf("Expected CreatedAt to be set, but it is zero")
+		}
+	})
+
+	t.Run("Stores due date from options", func(t *testing.T) {
+		store := NewMemoryStore()
+		due := time.Date(2026, 3, 6, 23, 59, 59, 0, time.UTC)
+
+		task, err := AddTask(store, "File taxes", AddOptions{DueAt: due})
+		if err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+
+		if !task.DueAt.Equal(due) {
+			t.Errorf("Expected due date %v, got %v", due, task.DueAt)
+		}
+	})
+
+	t.Run("Stores priority from options", func(t *testing.T) {
+		store := NewMemoryStore()
+
+		task, err := AddTask(store, "Fix outage", AddOptions{Priority: PriorityCritical})
+		if err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+
+		saved, err := store.Get(task.ID)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+
+		if saved.Priority != PriorityCritical {
+			t.Errorf("Expected priority %v, got %v", PriorityCritical, saved.Priority)
 		}
 	})
 
 	t.Run("Missing filename returns error", func(t *testing.T) {
-		err := AddTask("", "Buy groceries")
+		_, err := AddTask(NewJSONStore(""), "Buy groceries", AddOptions{})
 
 		if err == nil || err.Error() != "filename cannot be empty" {
 			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
@@ -32,7 +72,7 @@ func TestAddTask(t *testing.T) {
 		dir := t.TempDir()
 		filename := filepath.Join(dir, "tasks.json")
 
-		err := AddTask(filename, "")
+		_, err := AddTask(NewJSONStore(filename), "", AddOptions{})
 
 		if err == nil || err.Error() != "task description is required" {
 			t.Fatalf("Expected error %q, got %v", "task description is required", err)
@@ -41,7 +81,7 @@ func TestAddTask(t *testing.T) {
 }
 
 func TestListTasks(t *testing.T) {
-	t.Run("Prints tasks for non-empty file (no status filter)", func(t *testing.T) {
+	t.Run("Returns all tasks for non-empty file (no status filter)", func(t *testing.T) {
 		dir := t.TempDir()
 		filename := filepath.Join(dir, "tasks.json")
 
@@ -64,35 +104,26 @@ func TestListTasks(t *testing.T) {
 			t.Fatalf("Failed to write temp file: %v", err)
 		}
 
-		output := captureOutput(t, func() {
-			if err := ListTasks(filename, ""); err != nil {
-				t.Fatalf("ListTasks returned error: %v", err)
-			}
-		})
-
-		got := strings.TrimSpace(output)
-
-		mustContain := []string{
-			"ID",
-			"Status",
-			"Created",
-			"Description",
-			"1",
-			"2",
-			"Buy groceries",
-			"Cook dinner",
-			"todo",
-			"2025-01-12 15:04",
-		}
-
-		for _, s := range mustContain {
-			if !strings.Contains(got, s) {
-				t.Fatalf("expected output to contain %q, got:\n%s", s, got)
-			}
+		result, err := ListTasks(NewJSONStore(filename), Filter{})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+
+		if result.Total != 2 || len(result.Tasks) != 2 {
+			t.Fatalf("Expected 2 of 2 tasks, got %d of %d", len(result.Tasks), result.Total)
+		}
+
+		if result.Tasks[0].Description != "Buy groceries" || result.Tasks[1].Description != "Cook dinner" {
+			t.Errorf("unexpected tasks: %+v", result.Tasks)
+		}
+
+		want := time.Date(2025, 1, 12, 15, 4, 5, 0, time.UTC)
+		if !result.Tasks[0].CreatedAt.Equal(want) {
+			t.Errorf("CreatedAt: got %v, want %v", result.Tasks[0].CreatedAt, want)
 		}
 	})
 
-	t.Run("Prints message when no tasks found", func(t *testing.T) {
+	t.Run("Returns empty result when no tasks found", func(t *testing.T) {
 		dir := t.TempDir()
 		filename := filepath.Join(dir, "tasks.json")
 
@@ -100,28 +131,24 @@ func TestListTasks(t *testing.T) {
 			t.Fatalf("Failed to write temp file: %v", err)
 		}
 
-		output := captureOutput(t, func() {
-			if err := ListTasks(filename, ""); err != nil {
-				t.Fatalf("ListTasks returned error: %v", err)
-			}
-		})
-
-		got := strings.TrimSpace(output)
-		want := "No tasks found."
+		result, err := ListTasks(NewJSONStore(filename), Filter{})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
 
-		if !strings.Contains(got, want) {
-			t.Fatalf("Expected success message: %s, got %q", want, got)
+		if result.Total != 0 || len(result.Tasks) != 0 {
+			t.Fatalf("Expected no tasks, got %d of %d", len(result.Tasks), result.Total)
 		}
 	})
 
 	t.Run("Returns error when filename is empty", func(t *testing.T) {
-		err := ListTasks("", "")
+		_, err := ListTasks(NewJSONStore(""), Filter{})
 		if err == nil || err.Error() != "filename cannot be empty" {
 			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
 		}
 	})
 
-	t.Run("Prints only tasks with given status", func(t *testing.T) {
+	t.Run("Returns only tasks with given status", func(t *testing.T) {
 		dir := t.TempDir()
 		filename := filepath.Join(dir, "tasks.json")
 
@@ -150,26 +177,21 @@ func TestListTasks(t *testing.T) {
 			t.Fatalf("Failed to write temp file: %v", err)
 		}
 
-		output := captureOutput(t, func() {
-			if err := ListTasks(filename, "done"); err != nil {
-				t.Fatalf("ListTasks returned error: %v", err)
-			}
-		})
-
-		got := strings.TrimSpace(output)
-
-		if !strings.Contains(got, "Cook dinner") {
-			t.Fatalf("expected output to contain %q, got:\n%s", "Cook dinner", got)
+		result, err := ListTasks(NewJSONStore(filename), Filter{Status: "done"})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
 		}
-		if strings.Contains(got, "Buy groceries") {
-			t.Fatalf("did not expect output to contain %q when filtering by status %q, got:\n%s", "Buy groceries", "done", got)
+
+		if len(result.Tasks) != 1 || result.Tasks[0].Description != "Cook dinner" {
+			t.Fatalf("Expected only %q when filtering by status %q, got %+v", "Cook dinner", "done", result.Tasks)
 		}
-		if strings.Contains(got, "Clean kitchen") {
-			t.Fatalf("did not expect output to contain %q when filtering by status %q, got:\n%s", "Clean kitchen", "done", got)
+
+		if result.Total != 3 {
+			t.Errorf("Expected total of 3 tasks, got %d", result.Total)
 		}
 	})
 
-	t.Run("Prints message when no tasks with given status found", func(t *testing.T) {
+	t.Run("Returns empty result when no tasks with given status found", func(t *testing.T) {
 		dir := t.TempDir()
 		filename := filepath.Join(dir, "tasks.json")
 
@@ -186,18 +208,13 @@ func TestListTasks(t *testing.T) {
 			t.Fatalf("Failed to write temp file: %v", err)
 		}
 
-		output := captureOutput(t, func() {
-			if err := ListTasks(filename, "done"); err != nil {
-				t.Fatalf("ListTasks returned error: %v", err)
-			}
-		})
-
-		got := strings.TrimSpace(output)
-
-		want := `No tasks with status "done" found.`
+		result, err := ListTasks(NewJSONStore(filename), Filter{Status: "done"})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
 
-		if !strings.Contains(got, want) {
-			t.Fatalf("Expected message %q, got:\n%s", want, got)
+		if len(result.Tasks) != 0 || result.Total != 1 {
+			t.Fatalf("Expected 0 of 1 tasks, got %d of %d", len(result.Tasks), result.Total)
 		}
 	})
 }
@@ -215,6 +232,10 @@ func createTempTasksFile(t *testing.T, tasks []Task) string {
 	return path
 }
 
+func describe(description string) TaskChanges {
+	return TaskChanges{Description: &description}
+}
+
 func TestUpdateTask(t *testing.T) {
 	t.Run("Updates existing task successfully", func(t *testing.T) {
 		now := time.Now()
@@ -238,11 +259,15 @@ func TestUpdateTask(t *testing.T) {
 
 		newDescription := "Updated second task description"
 
-		err := UpdateTask(filename, 2, newDescription)
+		task, err := UpdateTask(NewJSONStore(filename), 2, TaskChanges{Description: &newDescription})
 		if err != nil {
 			t.Fatalf("UpdateTask returned error: %v", err)
 		}
 
+		if task.ID != 2 || task.Description != newDescription {
+			t.Errorf("unexpected returned task: %+v", task)
+		}
+
 		updatedTasks, err := Load(filename)
 		if err != nil {
 			t.Fatalf("Load returned error after update: %v", err)
@@ -291,7 +316,7 @@ func TestUpdateTask(t *testing.T) {
 
 		filename := createTempTasksFile(t, initialTasks)
 
-		err := UpdateTask(filename, 99, "Does not matter")
+		_, err := UpdateTask(NewJSONStore(filename), 99, describe("Does not matter"))
 
 		if !strings.Contains(err.Error(), "not found") {
 			t.Fatalf("Expected error to mention %q, got %q", "not found", err.Error())
@@ -302,36 +327,53 @@ func TestUpdateTask(t *testing.T) {
 		dir := t.TempDir()
 		filename := filepath.Join(dir, "tasks.json")
 
-		err := UpdateTask(filename, 1, "")
+		_, err := UpdateTask(NewJSONStore(filename), 1, describe(""))
 
 		if err == nil || err.Error() != "task description is required" {
 			t.Fatalf("Expected error %q, got %v", "task description is required", err)
 		}
 	})
 
-	t.Run("Returns error when filename is empty", func(t *testing.T) {
-		err := UpdateTask("", 1, "Some description")
+	t.Run("Sets and clears due date", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "First task", Status: "todo"})
+		due := time.Date(2026, 3, 6, 23, 59, 59, 0, time.UTC)
 
-		if err == nil || err.Error() != "filename cannot be empty" {
-			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
+		task, err := UpdateTask(store, 1, TaskChanges{DueAt: &due})
+		if err != nil {
+			t.Fatalf("UpdateTask returned error: %v", err)
 		}
-	})
-}
 
-func captureOutput(t *testing.T, fn func()) string {
-	t.Helper()
+		if !task.DueAt.Equal(due) || task.Description != "First task" {
+			t.Fatalf("unexpected task after setting due date: %+v", task)
+		}
+
+		var none time.Time
+		task, err = UpdateTask(store, 1, TaskChanges{DueAt: &none})
+		if err != nil {
+			t.Fatalf("UpdateTask returned error: %v", err)
+		}
+
+		if !task.DueAt.IsZero() {
+			t.Fatalf("Expected due date to be cleared, got %v", task.DueAt)
+		}
+	})
 
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	t.Run("Returns error when nothing to update", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "First task"})
 
-	fn()
+		_, err := UpdateTask(store, 1, TaskChanges{})
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("Expected validation error, got %v", err)
+		}
+	})
 
-	w.Close()
-	os.Stdout = old
+	t.Run("Returns error when filename is empty", func(t *testing.T) {
+		_, err := UpdateTask(NewJSONStore(""), 1, describe("Some description"))
 
-	out, _ := io.ReadAll(r)
-	return string(out)
+		if err == nil || err.Error() != "filename cannot be empty" {
+			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
+		}
+	})
 }
 
 func TestMarkTaskInProgress(t *testing.T) {
@@ -349,11 +391,15 @@ func TestMarkTaskInProgress(t *testing.T) {
 
 		filename := createTempTasksFile(t, initialTasks)
 
-		err := MarkTaskInProgress(filename, 1)
+		task, err := MarkTaskInProgress(NewJSONStore(filename), 1, false)
 		if err != nil {
 			t.Fatalf("MarkTaskInProgress returned error: %v", err)
 		}
 
+		if task.Status != "in progress" {
+			t.Errorf("Expected returned status %q, got %q", "in progress", task.Status)
+		}
+
 		updatedTasks, err := Load(filename)
 		if err != nil {
 			t.Fatalf("Load returned error after MarkTaskInProgress: %v", err)
@@ -369,7 +415,7 @@ func TestMarkTaskInProgress(t *testing.T) {
 	})
 
 	t.Run("Returns error when filename is empty", func(t *testing.T) {
-		err := MarkTaskInProgress("", 1)
+		_, err := MarkTaskInProgress(NewJSONStore(""), 1, false)
 
 		if err == nil || err.Error() != "filename cannot be empty" {
 			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
@@ -388,7 +434,7 @@ func TestMarkTaskInProgress(t *testing.T) {
 
 		filename := createTempTasksFile(t, initialTasks)
 
-		err := MarkTaskInProgress(filename, 99)
+		_, err := MarkTaskInProgress(NewJSONStore(filename), 99, false)
 		if err == nil {
 			t.Fatal("Expected error when marking non-existing task in progress, got nil")
 		}
@@ -414,11 +460,15 @@ func TestMarkTaskDone(t *testing.T) {
 
 		filename := createTempTasksFile(t, initialTasks)
 
-		err := MarkTaskDone(filename, 1)
+		task, err := MarkTaskDone(NewJSONStore(filename), 1, ChildrenRefuse)
 		if err != nil {
 			t.Fatalf("MarkTaskDone returned error: %v", err)
 		}
 
+		if task.Status != "done" {
+			t.Errorf("Expected returned status %q, got %q", "done", task.Status)
+		}
+
 		updatedTasks, err := Load(filename)
 		if err != nil {
 			t.Fatalf("Load returned error after MarkTaskDone: %v", err)
@@ -434,7 +484,7 @@ func TestMarkTaskDone(t *testing.T) {
 	})
 
 	t.Run("Returns error when filename is empty", func(t *testing.T) {
-		err := MarkTaskDone("", 1)
+		_, err := MarkTaskDone(NewJSONStore(""), 1, ChildrenRefuse)
 
 		if err == nil || err.Error() != "filename cannot be empty" {
 			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
@@ -453,7 +503,7 @@ func TestMarkTaskDone(t *testing.T) {
 
 		filename := createTempTasksFile(t, initialTasks)
 
-		err := MarkTaskDone(filename, 99)
+		_, err := MarkTaskDone(NewJSONStore(filename), 99, ChildrenRefuse)
 		if err == nil {
 			t.Fatal("Expected error when marking non-existing task done, got nil")
 		}
@@ -483,27 +533,31 @@ func TestDeleteTask(t *testing.T) {
 
 		filename := createTempTasksFile(t, initialTasks)
 
-		err := DeleteTask(filename, 1)
+		deleted, err := DeleteTask(NewJSONStore(filename), 1, ChildrenRefuse)
 		if err != nil {
 			t.Fatalf("DeleteTask returned error: %v", err)
 		}
 
+		if len(deleted) != 1 || deleted[0].ID != 1 || deleted[0].Description != "First task" {
+			t.Errorf("unexpected deleted tasks: %+v", deleted)
+		}
+
 		updatedTasks, err := Load(filename)
 		if err != nil {
 			t.Fatalf("Load returned error after DeleteTask: %v", err)
 		}
 
-		if len(updatedTasks) != 1 {
-			t.Fatalf("Expected 1 task after delete, got %d", len(updatedTasks))
+		if !equalIDs(liveIDs(updatedTasks), []int{2}) {
+			t.Fatalf("Expected remaining task IDs [2], got %v", liveIDs(updatedTasks))
 		}
 
-		if updatedTasks[0].ID != 2 {
-			t.Fatalf("Expected remaining task ID to be 2, got %d", updatedTasks[0].ID)
+		if !updatedTasks[0].IsDeleted() {
+			t.Fatalf("Expected task 1 to be in the trash, got %+v", updatedTasks[0])
 		}
 	})
 
 	t.Run("Returns error when filename is empty", func(t *testing.T) {
-		err := DeleteTask("", 1)
+		_, err := DeleteTask(NewJSONStore(""), 1, ChildrenRefuse)
 
 		if err == nil || err.Error() != "filename cannot be empty" {
 			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
@@ -522,7 +576,7 @@ func TestDeleteTask(t *testing.T) {
 
 		filename := createTempTasksFile(t, initialTasks)
 
-		err := DeleteTask(filename, 99)
+		_, err := DeleteTask(NewJSONStore(filename), 99, ChildrenRefuse)
 		if err == nil {
 			t.Fatal("Expected error when deleting non-existing task, got nil")
 		}
@@ -532,3 +586,29 @@ func TestDeleteTask(t *testing.T) {
 		}
 	})
 }
+
+func TestSetPriority(t *testing.T) {
+	t.Run("Sets priority on existing task", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "First task", Status: "todo"})
+
+		task, err := SetPriority(store, 1, PriorityCritical)
+		if err != nil {
+			t.Fatalf("SetPriority returned error: %v", err)
+		}
+
+		if task.Priority != PriorityCritical {
+			t.Errorf("Expected priority %v, got %v", PriorityCritical, task.Priority)
+		}
+
+		if task.UpdatedAt.IsZero() {
+			t.Errorf("Expected UpdatedAt to be set, but it is zero")
+		}
+	})
+
+	t.Run("Returns error when task not found", func(t *testing.T) {
+		_, err := SetPriority(NewMemoryStore(), 1, PriorityLow)
+		if !errors.Is(err, ErrTaskNotFound) {
+			t.Fatalf("Expected ErrTaskNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey orders tasks by one field, descending when Desc is set.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// sortFields compare two tasks by a field in ascending order. Tasks without
+// a due date or project come last either way.
+var sortFields = map[string]func(w Workflow, a, b Task) int{
+	"id": func(_ Workflow, a, b Task) int { return cmp.Compare(a.ID, b.ID) },
+	"status": func(w Workflow, a, b Task) int {
+		return cmp.Or(cmp.Compare(w.order(a.Status), w.order(b.Status)), strings.Compare(a.Status, b.Status))
+	},
+	"priority": func(_ Workflow, a, b Task) int { return cmp.Compare(a.Priority, b.Priority) },
+	"project":  func(_ Workflow, a, b Task) int { return strings.Compare(a.Project, b.Project) },
+	"due":      func(_ Workflow, a, b Task) int { return a.DueAt.Compare(b.DueAt) },
+	"created":  func(_ Workflow, a, b Task) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updated":  func(_ Workflow, a, b Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+	"description": func(_ Workflow, a, b Task) int {
+		return strings.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description))
+	},
+	"parent": func(_ Workflow, a, b Task) int { return cmp.Compare(a.ParentID, b.ParentID) },
+}
+
+// SortFields lists the fields tasks can be sorted by.
+var SortFields = []string{"id", "status", "priority", "project", "due", "created", "updated", "description", "parent"}
+
+// ParseSort reads a comma separated list of fields, each optionally
+// prefixed with "-" for descending or "+" for ascending order.
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+
+		key := SortKey{Field: strings.TrimLeft(part, "+-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortFields[key.Field]; !ok || len(part)-len(key.Field) > 1 {
+			return nil, &ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("cannot sort by %q (allowed: %s)", part, strings.Join(SortFields, ", ")),
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// SortTasks orders tasks by keys, then by ID. Statuses sort in workflow
+// order.
+func SortTasks(w Workflow, tasks []Task, keys []SortKey) {
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		for _, key := range keys {
+			if c := compareMissing(a, b, key.Field); c != 0 {
+				return c
+			}
+
+			c := sortFields[key.Field](w, a, b)
+			if key.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// compareMissing puts tasks that lack the field after those that have it.
+func compareMissing(a, b Task, field string) int {
+	var missingA, missingB bool
+	switch field {
+	case "due":
+		missingA, missingB = a.DueAt.IsZero(), b.DueAt.IsZero()
+	case "project":
+		missingA, missingB = a.Project == "", b.Project == ""
+	}
+
+	switch {
+	case missingA == missingB:
+		return 0
+	case missingA:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Page returns at most limit tasks starting at offset; a limit of zero
+// means no limit.
+func Page(tasks []Task, offset, limit int) []Task {
+	if offset >= len(tasks) {
+		return []Task{}
+	}
+
+	tasks = tasks[offset:]
+	if limit > 0 && limit < len(tasks) {
+		tasks = tasks[:limit]
+	}
+
+	return tasks
+}
@@ -0,0 +1,92 @@
+package tasks
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSort(t *testing.T) {
+	t.Run("Reads fields and directions", func(t *testing.T) {
+		keys, err := ParseSort("-priority, created,+ID")
+		if err != nil {
+			t.Fatalf("ParseSort returned error: %v", err)
+		}
+
+		want := []SortKey{{Field: "priority", Desc: true}, {Field: "created"}, {Field: "id"}}
+		if !slices.Equal(keys, want) {
+			t.Errorf("Expected %v, got %v", want, keys)
+		}
+	})
+
+	t.Run("Rejects unknown fields", func(t *testing.T) {
+		for _, spec := range []string{"bogus", "", "priority,", "--priority", "-+id"} {
+			if _, err := ParseSort(spec); !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected ErrValidation for %q, got %v", spec, err)
+			}
+		}
+	})
+}
+
+func TestSortTasks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	list := func() []Task {
+		return []Task{
+			{ID: 1, Priority: PriorityLow, CreatedAt: day(3), Project: "web"},
+			{ID: 2, Priority: PriorityHigh, CreatedAt: day(2), DueAt: day(9)},
+			{ID: 3, Priority: PriorityHigh, CreatedAt: day(1), DueAt: day(8), Project: "docs"},
+			{ID: 4, Priority: PriorityLow, CreatedAt: day(1), UpdatedAt: day(5)},
+		}
+	}
+
+	cases := []struct {
+		spec string
+		want []int
+	}{
+		{"-priority,created", []int{3, 2, 4, 1}},
+		{"priority,-created", []int{1, 4, 2, 3}},
+		{"-id", []int{4, 3, 2, 1}},
+		{"due", []int{3, 2, 1, 4}},
+		{"-due", []int{2, 3, 1, 4}},
+		{"project", []int{3, 1, 2, 4}},
+		{"-updated", []int{4, 1, 2, 3}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			keys, err := ParseSort(c.spec)
+			if err != nil {
+				t.Fatalf("ParseSort returned error: %v", err)
+			}
+
+			tasks := list()
+			SortTasks(Workflow{}, tasks, keys)
+			if got := ids(tasks); !slices.Equal(got, c.want) {
+				t.Errorf("Expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	list := []Task{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+
+	cases := []struct {
+		offset, limit int
+		want          []int
+	}{
+		{0, 0, []int{1, 2, 3, 4}},
+		{0, 2, []int{1, 2}},
+		{1, 2, []int{2, 3}},
+		{3, 5, []int{4}},
+		{4, 1, []int{}},
+		{9, 0, []int{}},
+	}
+
+	for _, c := range cases {
+		if got := ids(Page(list, c.offset, c.limit)); !slices.Equal(got, c.want) {
+			t.Errorf("Page(%d, %d): expected %v, got %v", c.offset, c.limit, c.want, got)
+		}
+	}
+}
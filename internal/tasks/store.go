@@ -1,40 +1,259 @@
 package tasks
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"time"
 )
 
-func Load(file string) ([]Task, error) {
-	var tasks []Task
+// FileVersion is the task file format this binary writes and the newest one
+// it reads. Files from before versioning are a bare JSON array of tasks.
+const FileVersion = 1
 
-	data, err := os.ReadFile(file)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return []Task{}, nil
+// taskFile is the envelope a JSON task file is stored in.
+type taskFile struct {
+	Version int    `json:"version"`
+	Meta    Meta   `json:"meta"`
+	Tasks   []Task `json:"tasks"`
+}
+
+type Store interface {
+	Load() ([]Task, error)
+	Save(tasks []Task) error
+	Get(ID int) (Task, error)
+	Update(fn func(tasks []Task) ([]Task, error)) error
+	// LoadMeta returns the store metadata, such as the ID counter.
+	LoadMeta() (Meta, error)
+	// UpdateMeta is Update with access to the metadata. Changes fn makes to
+	// meta are saved together with the tasks.
+	UpdateMeta(fn func(tasks []Task, meta *Meta) ([]Task, error)) error
+}
+
+type JSONStore struct {
+	file string
+
+	LockTimeout time.Duration
+}
+
+func NewJSONStore(file string) *JSONStore {
+	return &JSONStore{file: file, LockTimeout: DefaultLockTimeout}
+}
+
+func (s *JSONStore) File() string {
+	return s.file
+}
+
+func (s *JSONStore) Load() ([]Task, error) {
+	if s.file == "" {
+		return nil, errEmptyFilename()
+	}
+
+	return Load(s.file)
+}
+
+func (s *JSONStore) Save(tasks []Task) error {
+	if s.file == "" {
+		return errEmptyFilename()
+	}
+
+	return s.withLock(func() error {
+		_, meta, err := loadFile(s.file)
+		if err != nil {
+			return err
 		}
-		
-		return nil, err
+
+		return saveFile(s.file, tasks, meta)
+	})
+}
+
+func (s *JSONStore) Get(ID int) (Task, error) {
+	tasks, err := s.Load()
+	if err != nil {
+		return Task{}, err
 	}
 
-	if len(data) == 0 {
-		return []Task{}, nil
+	return findTask(tasks, ID)
+}
+
+func (s *JSONStore) Update(fn func(tasks []Task) ([]Task, error)) error {
+	return s.UpdateMeta(func(tasks []Task, _ *Meta) ([]Task, error) {
+		return fn(tasks)
+	})
+}
+
+func (s *JSONStore) LoadMeta() (Meta, error) {
+	if s.file == "" {
+		return Meta{}, errEmptyFilename()
+	}
+
+	_, meta, err := loadFile(s.file)
+	return meta, err
+}
+
+func (s *JSONStore) UpdateMeta(fn func(tasks []Task, meta *Meta) ([]Task, error)) error {
+	if s.file == "" {
+		return errEmptyFilename()
 	}
 
-	err = json.Unmarshal(data, &tasks)
+	return s.withLock(func() error {
+		tasks, meta, err := loadFile(s.file)
+		if err != nil {
+			return err
+		}
+
+		tasks, err = fn(tasks, &meta)
+		if err != nil {
+			return err
+		}
+
+		return saveFile(s.file, tasks, meta)
+	})
+}
+
+func (s *JSONStore) withLock(fn func() error) error {
+	lock, err := acquireLock(LockFile(s.file), s.LockTimeout)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	err = fn()
+	if unlockErr := lock.release(); err == nil {
+		err = unlockErr
+	}
+
+	return err
+}
+
+func errEmptyFilename() error {
+	return &ValidationError{Field: "file", Message: "filename cannot be empty"}
+}
+
+// findTask looks up a task for Store.Get, which also returns tasks in the
+// trash.
+func findTask(tasks []Task, ID int) (Task, error) {
+	i := indexOfAny(tasks, ID)
+	if i < 0 {
+		return Task{}, &TaskNotFoundError{ID: ID}
 	}
 
-	return tasks, nil
+	return tasks[i], nil
 }
 
+// Load reads the tasks from file, in either the current or the legacy
+// format.
+func Load(file string) ([]Task, error) {
+	tasks, _, err := loadFile(file)
+	return tasks, err
+}
+
+// Save writes tasks to file in the current format, with an ID counter
+// derived from the tasks themselves. JSONStore keeps the stored counter.
 func Save(file string, tasks []Task) error {
-	data, err := json.Marshal(tasks)
+	return saveFile(file, tasks, Meta{})
+}
+
+func loadFile(file string) ([]Task, Meta, error) {
+	var content taskFile
+
+	data, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, Meta{}, err
+	}
+
+	switch trimmed := bytes.TrimSpace(data); {
+	case len(trimmed) == 0:
+	case trimmed[0] == '[':
+		if err := json.Unmarshal(data, &content.Tasks); err != nil {
+			return nil, Meta{}, corruptJSON(file, err)
+		}
+
+		if content.Meta, err = loadLegacyMeta(file); err != nil {
+			return nil, Meta{}, err
+		}
+	default:
+		var header struct {
+			Version int `json:"version"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			return nil, Meta{}, corruptJSON(file, err)
+		}
+
+		if header.Version > FileVersion {
+			return nil, Meta{}, &UnsupportedVersionError{File: file, Version: header.Version}
+		}
+		if header.Version < 1 {
+			return nil, Meta{}, &CorruptStoreError{File: file, Err: errors.New("missing file format version")}
+		}
+
+		if err := json.Unmarshal(data, &content); err != nil {
+			return nil, Meta{}, corruptJSON(file, err)
+		}
+	}
+
+	if content.Tasks == nil {
+		content.Tasks = []Task{}
+	}
+
+	if err := checkUniqueIDs(content.Tasks); err != nil {
+		return nil, Meta{}, &CorruptStoreError{File: file, Err: err}
+	}
+
+	content.Meta.Observe(content.Tasks)
+	return content.Tasks, content.Meta, nil
+}
+
+// saveFile always writes the current format, so a legacy file is upgraded by
+// the first change made to it.
+func saveFile(file string, tasks []Task, meta Meta) error {
+	if err := checkUniqueIDs(tasks); err != nil {
+		return err
+	}
+
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	meta.Observe(tasks)
+
+	data, err := json.Marshal(taskFile{Version: FileVersion, Meta: meta, Tasks: tasks})
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(file, data, 0644)
+	if err := writeFileAtomic(file, data, 0644); err != nil {
+		return err
+	}
+
+	return removeLegacyMeta(file)
+}
+
+// CopyTasks copies all tasks, including the trash, the ID counter and the
+// saved views from src into the empty store dst.
+func CopyTasks(dst, src Store) (int, error) {
+	tasks, err := src.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	srcMeta, err := src.LoadMeta()
+	if err != nil {
+		return 0, err
+	}
+
+	err = dst.UpdateMeta(func(existing []Task, meta *Meta) ([]Task, error) {
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("destination already contains %d tasks", len(existing))
+		}
+
+		meta.NextID = max(meta.NextID, srcMeta.NextID)
+		meta.Views = srcMeta.Views
+		return tasks, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(tasks), nil
 }
@@ -2,8 +2,10 @@ package tasks
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -132,10 +134,14 @@ func TestSave(t *testing.T) {
 			t.Fatalf("failed to read saved file: %v", err)
 		}
 
-		var saved []Task
-		if err := json.Unmarshal(data, &saved); err != nil {
+		var content taskFile
+		if err := json.Unmarshal(data, &content); err != nil {
 			t.Fatalf("failed to unmarshal saved JSON: %v", err)
 		}
+		if content.Version != FileVersion || content.Meta.NextID != 3 {
+			t.Errorf("unexpected envelope: version %d, meta %+v", content.Version, content.Meta)
+		}
+		saved := content.Tasks
 
 		// Validate content
 		if len(saved) != 2 {
@@ -157,3 +163,153 @@ func TestSave(t *testing.T) {
 		}
 	})
 }
+
+func TestFileVersions(t *testing.T) {
+	t.Run("Upgrades a legacy file on the next save", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "tasks.json")
+		if err := os.WriteFile(filename, []byte(`[{"id": 1, "description": "Old"}]`), 0o644); err != nil {
+			t.Fatalf("Failed to write temp file: %v", err)
+		}
+		if err := os.WriteFile(filename+legacyMetaSuffix, []byte(`{"next_id": 5}`), 0o644); err != nil {
+			t.Fatalf("Failed to write meta file: %v", err)
+		}
+
+		store := NewJSONStore(filename)
+		task, err := AddTask(store, "New", AddOptions{})
+		if err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+		if task.ID != 5 {
+			t.Errorf("Expected legacy counter to give ID 5, got %d", task.ID)
+		}
+
+		data, _ := os.ReadFile(filename)
+		var content taskFile
+		if err := json.Unmarshal(data, &content); err != nil {
+			t.Fatalf("Expected an envelope after saving, got %s", data)
+		}
+		if content.Version != FileVersion || content.Meta.NextID != 6 || len(content.Tasks) != 2 {
+			t.Errorf("unexpected envelope: %+v", content)
+		}
+
+		if _, err := os.Stat(filename + legacyMetaSuffix); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected legacy meta file to be removed, got %v", err)
+		}
+	})
+
+	t.Run("Refuses a file from a newer version", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "tasks.json")
+		newer := `{"version": 99, "tasks": []}`
+		if err := os.WriteFile(filename, []byte(newer), 0o644); err != nil {
+			t.Fatalf("Failed to write temp file: %v", err)
+		}
+
+		_, err := Load(filename)
+		var versionErr *UnsupportedVersionError
+		if !errors.As(err, &versionErr) || versionErr.Version != 99 {
+			t.Fatalf("Expected UnsupportedVersionError, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "upgrade") {
+			t.Errorf("Expected error to suggest upgrading, got %q", err)
+		}
+
+		if err := NewJSONStore(filename).Save([]Task{{ID: 1}}); err == nil {
+			t.Error("Expected Save to refuse overwriting a newer file")
+		}
+		if data, _ := os.ReadFile(filename); string(data) != newer {
+			t.Errorf("Expected file to be left alone, got %s", data)
+		}
+	})
+
+	t.Run("Rejects an envelope without a version", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "tasks.json")
+		if err := os.WriteFile(filename, []byte(`{"tasks": []}`), 0o644); err != nil {
+			t.Fatalf("Failed to write temp file: %v", err)
+		}
+
+		if _, err := Load(filename); !errors.Is(err, ErrCorruptStore) {
+			t.Errorf("Expected ErrCorruptStore, got %v", err)
+		}
+	})
+}
+
+func TestJSONStoreGet(t *testing.T) {
+	t.Run("Returns task with given ID", func(t *testing.T) {
+		filename := createTempTasksFile(t, []Task{
+			{ID: 1, Description: "Buy groceries", Status: "todo"},
+			{ID: 2, Description: "Cook dinner", Status: "todo"},
+		})
+
+		task, err := NewJSONStore(filename).Get(2)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+
+		if task.Description != "Cook dinner" {
+			t.Errorf("Description: got %q, want %q", task.Description, "Cook dinner")
+		}
+	})
+
+	t.Run("Returns error when task not found", func(t *testing.T) {
+		filename := createTempTasksFile(t, []Task{{ID: 1, Description: "Buy groceries"}})
+
+		_, err := NewJSONStore(filename).Get(99)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Fatalf("Expected error to mention %q, got %v", "not found", err)
+		}
+	})
+}
+
+func TestJSONStoreUpdate(t *testing.T) {
+	t.Run("Saves tasks returned by fn", func(t *testing.T) {
+		filename := createTempTasksFile(t, []Task{{ID: 1, Description: "Buy groceries"}})
+		store := NewJSONStore(filename)
+
+		err := store.Update(func(tasks []Task) ([]Task, error) {
+			return append(tasks, Task{ID: 2, Description: "Cook dinner"}), nil
+		})
+		if err != nil {
+			t.Fatalf("Update returned error: %v", err)
+		}
+
+		tasks, err := Load(filename)
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+
+		if len(tasks) != 2 {
+			t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+		}
+	})
+
+	t.Run("Does not save when fn returns error", func(t *testing.T) {
+		filename := createTempTasksFile(t, []Task{{ID: 1, Description: "Buy groceries"}})
+		store := NewJSONStore(filename)
+
+		err := store.Update(func(tasks []Task) ([]Task, error) {
+			return nil, errors.New("boom")
+		})
+		if err == nil || err.Error() != "boom" {
+			t.Fatalf("Expected error %q, got %v", "boom", err)
+		}
+
+		tasks, err := Load(filename)
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+
+		if len(tasks) != 1 {
+			t.Fatalf("Expected 1 task, got %d", len(tasks))
+		}
+	})
+
+	t.Run("Returns error when filename is empty", func(t *testing.T) {
+		err := NewJSONStore("").Update(func(tasks []Task) ([]Task, error) {
+			return tasks, nil
+		})
+
+		if err == nil || err.Error() != "filename cannot be empty" {
+			t.Fatalf("Expected error %q, got %v", "filename cannot be empty", err)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ChildPolicy decides what happens to subtasks when their parent is deleted
+// or marked done.
+type ChildPolicy string
+
+const (
+	// ChildrenRefuse rejects the operation while the parent has subtasks
+	// (for done: subtasks that are not done yet).
+	ChildrenRefuse ChildPolicy = "refuse"
+	// ChildrenCascade applies the operation to all subtasks as well.
+	ChildrenCascade ChildPolicy = "cascade"
+	// ChildrenReparent moves the affected subtasks up to the parent's parent.
+	ChildrenReparent ChildPolicy = "reparent"
+)
+
+func ParseChildPolicy(value string) (ChildPolicy, error) {
+	switch policy := ChildPolicy(strings.ToLower(value)); policy {
+	case ChildrenRefuse, ChildrenCascade, ChildrenReparent:
+		return policy, nil
+	default:
+		return "", &ValidationError{
+			Field:   "children",
+			Message: fmt.Sprintf("invalid children policy %q (allowed: refuse, cascade, reparent)", value),
+		}
+	}
+}
+
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type TreeEntry struct {
+	Task  Task
+	Depth int
+}
+
+// descendants returns the IDs of all subtasks of ID that are not in the
+// trash, depth first.
+func descendants(tasks []Task, ID int) []int {
+	var result []int
+	var walk func(parent int)
+	seen := map[int]bool{ID: true}
+
+	walk = func(parent int) {
+		for _, task := range tasks {
+			if task.ParentID == parent && !seen[task.ID] && !task.IsDeleted() {
+				seen[task.ID] = true
+				result = append(result, task.ID)
+				walk(task.ID)
+			}
+		}
+	}
+	walk(ID)
+
+	return result
+}
+
+// subtasksFirst orders IDs so that every task comes after its subtasks,
+// keeping ascending IDs within the same depth.
+func subtasksFirst(tasks []Task, IDs []int) []int {
+	parents := make(map[int]int, len(tasks))
+	for _, task := range tasks {
+		parents[task.ID] = task.ParentID
+	}
+
+	depth := make(map[int]int, len(IDs))
+	for _, ID := range IDs {
+		seen := map[int]bool{ID: true}
+		for parent := parents[ID]; parent != 0 && !seen[parent]; parent = parents[parent] {
+			seen[parent] = true
+			depth[ID]++
+		}
+	}
+
+	ordered := slices.Clone(IDs)
+	slices.SortStableFunc(ordered, func(a, b int) int {
+		return cmp.Or(cmp.Compare(depth[b], depth[a]), cmp.Compare(a, b))
+	})
+
+	return ordered
+}
+
+// SubtaskProgress counts done and total subtasks (at any depth) of every
+// task that has at least one.
+func SubtaskProgress(w Workflow, tasks []Task) map[int]Progress {
+	progress := map[int]Progress{}
+
+	for _, task := range tasks {
+		if task.IsDeleted() {
+			continue
+		}
+
+		sub := descendants(tasks, task.ID)
+		if len(sub) == 0 {
+			continue
+		}
+
+		p := Progress{Total: len(sub)}
+		for _, ID := range sub {
+			if i, err := indexOf(tasks, ID); err == nil && w.IsClosed(tasks[i].Status) {
+				p.Done++
+			}
+		}
+		progress[task.ID] = p
+	}
+
+	return progress
+}
+
+// TreeOrder arranges tasks so that every subtask follows its parent, keeping
+// the given order among siblings. A task whose parent is not in the list is
+// shown at the top level.
+func TreeOrder(tasks []Task) []TreeEntry {
+	present := map[int]bool{}
+	for _, task := range tasks {
+		present[task.ID] = true
+	}
+
+	children := map[int][]Task{}
+	var roots []Task
+	for _, task := range tasks {
+		if task.ParentID != 0 && present[task.ParentID] && task.ParentID != task.ID {
+			children[task.ParentID] = append(children[task.ParentID], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	entries := make([]TreeEntry, 0, len(tasks))
+	visited := map[int]bool{}
+
+	var walk func(task Task, depth int)
+	walk = func(task Task, depth int) {
+		if visited[task.ID] {
+			return
+		}
+		visited[task.ID] = true
+
+		entries = append(entries, TreeEntry{Task: task, Depth: depth})
+		for _, child := range children[task.ID] {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		walk(root, 0)
+	}
+
+	// Anything left over is part of a parent cycle; show it flat rather
+	// than dropping it.
+	for _, task := range tasks {
+		if !visited[task.ID] {
+			walk(task, 0)
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,170 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+)
+
+func subtaskStore() *MemoryStore {
+	return NewMemoryStore(
+		Task{ID: 1, Status: "todo"},
+		Task{ID: 2, Status: "done", ParentID: 1},
+		Task{ID: 3, Status: "todo", ParentID: 1},
+		Task{ID: 4, Status: "todo", ParentID: 3},
+		Task{ID: 5, Status: "todo"},
+	)
+}
+
+func TestAddSubtask(t *testing.T) {
+	store := NewMemoryStore(Task{ID: 1, Status: "todo"})
+
+	task, err := AddTask(store, "child", AddOptions{ParentID: 1})
+	if err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+	if task.ParentID != 1 {
+		t.Errorf("Expected parent 1, got %d", task.ParentID)
+	}
+
+	if _, err := AddTask(store, "orphan", AddOptions{ParentID: 42}); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for missing parent, got %v", err)
+	}
+}
+
+func TestDeleteTaskWithChildren(t *testing.T) {
+	t.Run("Refuses by default", func(t *testing.T) {
+		store := subtaskStore()
+
+		_, err := DeleteTask(store, 1, ChildrenRefuse)
+		if !errors.Is(err, ErrConflict) {
+			t.Fatalf("Expected ErrConflict, got %v", err)
+		}
+
+		tasks, _ := store.Load()
+		if len(tasks) != 5 {
+			t.Errorf("Expected nothing deleted, got %d tasks left", len(tasks))
+		}
+	})
+
+	t.Run("Cascades to all descendants", func(t *testing.T) {
+		store := subtaskStore()
+
+		deleted, err := DeleteTask(store, 1, ChildrenCascade)
+		if err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+		if !equalIDs(ids(deleted), []int{1, 2, 3, 4}) {
+			t.Errorf("Expected deleted IDs [1 2 3 4], got %v", ids(deleted))
+		}
+
+		tasks, _ := store.Load()
+		if !equalIDs(liveIDs(tasks), []int{5}) {
+			t.Errorf("Expected remaining IDs [5], got %v", liveIDs(tasks))
+		}
+	})
+
+	t.Run("Reparents children to the grandparent", func(t *testing.T) {
+		store := subtaskStore()
+
+		if _, err := DeleteTask(store, 3, ChildrenReparent); err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+
+		task, _ := store.Get(4)
+		if task.ParentID != 1 {
+			t.Errorf("Expected task 4 to move under 1, got parent %d", task.ParentID)
+		}
+	})
+}
+
+func TestMarkTaskDoneWithChildren(t *testing.T) {
+	t.Run("Refuses while subtasks are unfinished", func(t *testing.T) {
+		store := subtaskStore()
+
+		if _, err := MarkTaskDone(store, 1, ChildrenRefuse); !errors.Is(err, ErrConflict) {
+			t.Fatalf("Expected ErrConflict, got %v", err)
+		}
+
+		if _, err := MarkTaskDone(store, 4, ChildrenRefuse); err != nil {
+			t.Errorf("Expected leaf task to be marked done, got %v", err)
+		}
+	})
+
+	t.Run("Cascades to unfinished subtasks", func(t *testing.T) {
+		store := subtaskStore()
+
+		if _, err := MarkTaskDone(store, 1, ChildrenCascade); err != nil {
+			t.Fatalf("MarkTaskDone returned error: %v", err)
+		}
+
+		tasks, _ := store.Load()
+		for _, task := range tasks[:4] {
+			if task.Status != "done" {
+				t.Errorf("Expected task %d to be done, got %q", task.ID, task.Status)
+			}
+		}
+	})
+
+	t.Run("Reparents unfinished subtrees", func(t *testing.T) {
+		store := subtaskStore()
+		store.Update(func(tasks []Task) ([]Task, error) {
+			tasks[0].ParentID = 5
+			tasks[2].Status = "done"
+			return tasks, nil
+		})
+
+		if _, err := MarkTaskDone(store, 1, ChildrenReparent); err != nil {
+			t.Fatalf("MarkTaskDone returned error: %v", err)
+		}
+
+		task, _ := store.Get(4)
+		if task.ParentID != 5 || task.Status != "todo" {
+			t.Errorf("Expected task 4 to stay todo under 5, got %+v", task)
+		}
+	})
+}
+
+func TestSubtaskProgress(t *testing.T) {
+	progress := SubtaskProgress(Workflow{}, subtaskStore().tasks)
+
+	if got := progress[1]; got != (Progress{Done: 1, Total: 3}) {
+		t.Errorf("Expected progress 1/3 for task 1, got %+v", got)
+	}
+	if got := progress[3]; got != (Progress{Done: 0, Total: 1}) {
+		t.Errorf("Expected progress 0/1 for task 3, got %+v", got)
+	}
+	if _, ok := progress[5]; ok {
+		t.Errorf("Expected no progress for task without subtasks")
+	}
+}
+
+func TestTreeOrder(t *testing.T) {
+	list := []Task{
+		{ID: 4, ParentID: 3},
+		{ID: 5},
+		{ID: 3, ParentID: 1},
+		{ID: 1},
+		{ID: 6, ParentID: 99},
+	}
+
+	var got []int
+	var depths []int
+	for _, entry := range TreeOrder(list) {
+		got = append(got, entry.Task.ID)
+		depths = append(depths, entry.Depth)
+	}
+
+	if !equalIDs(got, []int{5, 1, 3, 4, 6}) {
+		t.Errorf("Expected order [5 1 3 4 6], got %v", got)
+	}
+	if !equalIDs(depths, []int{0, 0, 1, 2, 0}) {
+		t.Errorf("Expected depths [0 0 1 2 0], got %v", depths)
+	}
+
+	t.Run("Keeps tasks in a parent cycle", func(t *testing.T) {
+		entries := TreeOrder([]Task{{ID: 1, ParentID: 2}, {ID: 2, ParentID: 1}})
+		if len(entries) != 2 {
+			t.Errorf("Expected 2 entries, got %d", len(entries))
+		}
+	})
+}
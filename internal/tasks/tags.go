@@ -0,0 +1,126 @@
+package tasks
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]*$`)
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTag lower-cases a tag and strips a leading "+", so "+Backend"
+// and "backend" name the same tag.
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+
+	if !tagPattern.MatchString(normalized) {
+		return "", &ValidationError{Field: "tag", Message: fmt.Sprintf("invalid tag %q", tag)}
+	}
+
+	return normalized, nil
+}
+
+// ExtractTags removes +tag words from a description and returns the
+// remaining text together with the normalized tags.
+func ExtractTags(description string) (string, []string) {
+	var words []string
+	var tags []string
+
+	for _, word := range strings.Fields(description) {
+		if strings.HasPrefix(word, "+") && len(word) > 1 {
+			if tag, err := NormalizeTag(word); err == nil {
+				tags = append(tags, tag)
+				continue
+			}
+		}
+		words = append(words, word)
+	}
+
+	return strings.Join(words, " "), mergeTags(nil, tags, nil)
+}
+
+func (t Task) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+func EditTags(store Store, ID int, add []string, remove []string) (Task, error) {
+	add, err := normalizeTags(add)
+	if err != nil {
+		return Task{}, err
+	}
+
+	remove, err = normalizeTags(remove)
+	if err != nil {
+		return Task{}, err
+	}
+
+	return updateTask(store, ID, func(task *Task) {
+		task.Tags = mergeTags(task.Tags, add, remove)
+		task.UpdatedAt = time.Now()
+	})
+}
+
+func TagCounts(store Store) ([]TagCount, error) {
+	tasks, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, task := range tasks {
+		if task.IsDeleted() {
+			continue
+		}
+
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, TagCount{Tag: tag, Count: count})
+	}
+
+	slices.SortFunc(result, func(a, b TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Tag, b.Tag))
+	})
+
+	return result, nil
+}
+
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		n, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, n)
+	}
+
+	return normalized, nil
+}
+
+// mergeTags returns the sorted, de-duplicated union of current and add,
+// minus remove. It returns nil rather than an empty slice so untagged tasks
+// serialize without a tags field.
+func mergeTags(current, add, remove []string) []string {
+	var merged []string
+	for _, tag := range slices.Concat(current, add) {
+		if !slices.Contains(remove, tag) {
+			merged = append(merged, tag)
+		}
+	}
+
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
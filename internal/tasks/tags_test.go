@@ -0,0 +1,131 @@
+package tasks
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	for input, want := range map[string]string{
+		"backend":   "backend",
+		"+Backend":  "backend",
+		"sprint-42": "sprint-42",
+		"area:ui":   "area:ui",
+	} {
+		got, err := NormalizeTag(input)
+		if err != nil {
+			t.Errorf("NormalizeTag(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeTag(%q): got %q, want %q", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "+", "two words", "-dash", "a/b"} {
+		if _, err := NormalizeTag(input); !errors.Is(err, ErrValidation) {
+			t.Errorf("NormalizeTag(%q): expected validation error, got %v", input, err)
+		}
+	}
+}
+
+func TestExtractTags(t *testing.T) {
+	description, tags := ExtractTags("Fix +Backend login +urgent bug +backend for c++")
+
+	if description != "Fix login bug for c++" {
+		t.Errorf("description: got %q", description)
+	}
+
+	if !slices.Equal(tags, []string{"backend", "urgent"}) {
+		t.Errorf("tags: got %v, want [backend urgent]", tags)
+	}
+}
+
+func TestAddTaskWithTags(t *testing.T) {
+	store := NewMemoryStore()
+
+	task, err := AddTask(store, "Fix login", AddOptions{Tags: []string{"Backend", "+urgent", "backend"}})
+	if err != nil {
+		t.Fatalf("AddTask returned error: %v", err)
+	}
+
+	if !slices.Equal(task.Tags, []string{"backend", "urgent"}) {
+		t.Fatalf("tags: got %v, want [backend urgent]", task.Tags)
+	}
+
+	if _, err := AddTask(store, "Bad tag", AddOptions{Tags: []string{"no spaces"}}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected validation error for invalid tag, got %v", err)
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	t.Run("Adds and removes tags", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "Fix login", Tags: []string{"backend", "blocked"}})
+
+		task, err := EditTags(store, 1, []string{"urgent"}, []string{"blocked"})
+		if err != nil {
+			t.Fatalf("EditTags returned error: %v", err)
+		}
+
+		if !slices.Equal(task.Tags, []string{"backend", "urgent"}) {
+			t.Fatalf("tags: got %v, want [backend urgent]", task.Tags)
+		}
+	})
+
+	t.Run("Removing last tag leaves nil", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Description: "Fix login", Tags: []string{"backend"}})
+
+		task, err := EditTags(store, 1, nil, []string{"backend"})
+		if err != nil {
+			t.Fatalf("EditTags returned error: %v", err)
+		}
+
+		if task.Tags != nil {
+			t.Fatalf("Expected nil tags, got %v", task.Tags)
+		}
+	})
+
+	t.Run("Returns error when task not found", func(t *testing.T) {
+		_, err := EditTags(NewMemoryStore(), 1, []string{"a"}, nil)
+		if !errors.Is(err, ErrTaskNotFound) {
+			t.Fatalf("Expected ErrTaskNotFound, got %v", err)
+		}
+	})
+}
+
+func TestTagCounts(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Tags: []string{"backend", "urgent"}},
+		Task{ID: 2, Tags: []string{"backend"}},
+		Task{ID: 3, Tags: []string{"frontend"}},
+		Task{ID: 4},
+	)
+
+	counts, err := TagCounts(store)
+	if err != nil {
+		t.Fatalf("TagCounts returned error: %v", err)
+	}
+
+	want := []TagCount{{"backend", 2}, {"frontend", 1}, {"urgent", 1}}
+	if !slices.Equal(counts, want) {
+		t.Fatalf("got %v, want %v", counts, want)
+	}
+}
+
+func TestListTasksByTag(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "todo", Tags: []string{"backend"}},
+		Task{ID: 2, Status: "todo", Tags: []string{"backend", "blocked"}},
+		Task{ID: 3, Status: "todo", Tags: []string{"frontend"}},
+	)
+
+	result, err := ListTasks(store, Filter{Tags: []string{"backend"}, ExcludeTags: []string{"blocked"}})
+	if err != nil {
+		t.Fatalf("ListTasks returned error: %v", err)
+	}
+
+	if got := ids(result.Tasks); !equalIDs(got, []int{1}) {
+		t.Fatalf("got %v, want [1]", got)
+	}
+}
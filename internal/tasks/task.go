@@ -4,8 +4,27 @@ import "time"
 
 type Task struct {
 	ID          int       `json:"id"`
+	UID         string    `json:"uid,omitempty"`
 	Description string    `json:"description"`
 	Status      string    `json:"status"`
+	Priority    Priority  `json:"priority,omitzero"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	DueAt       time.Time `json:"due_at,omitzero"`
+	Tags        []string  `json:"tags,omitempty"`
+	Project     string    `json:"project,omitempty"`
+	ParentID    int       `json:"parent_id,omitempty"`
+	DependsOn   []int     `json:"depends_on,omitempty"`
+	History     []Event   `json:"history,omitempty"`
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt time.Time `json:"deleted_at,omitzero"`
+}
+
+// IsOverdue reports whether the task is past its due date and not closed.
+func (t Task) IsOverdue(w Workflow, now time.Time) bool {
+	return !t.DueAt.IsZero() && !w.IsClosed(t.Status) && t.DueAt.Before(now)
+}
+
+func (t Task) IsDeleted() bool {
+	return !t.DeletedAt.IsZero()
 }
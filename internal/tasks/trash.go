@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Trash returns the deleted tasks, most recently deleted first.
+func Trash(store Store) ([]Task, error) {
+	tasks, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := []Task{}
+	for _, task := range tasks {
+		if task.IsDeleted() {
+			trashed = append(trashed, task)
+		}
+	}
+
+	slices.SortStableFunc(trashed, func(a, b Task) int {
+		return cmp.Or(b.DeletedAt.Compare(a.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return trashed, nil
+}
+
+// RestoreTask takes a task out of the trash under its original ID, together
+// with the subtasks that were deleted along with it and any parents still in
+// the trash, so a subtask never hangs off a deleted task. The requested task
+// is first in the returned slice.
+func RestoreTask(store Store, ID int) ([]Task, error) {
+	var restored []Task
+	err := store.Update(func(tasks []Task) ([]Task, error) {
+		i := indexOfAny(tasks, ID)
+		if i < 0 || !tasks[i].IsDeleted() {
+			return nil, &TaskNotFoundError{ID: ID}
+		}
+
+		for _, r := range deletedWith(tasks, i) {
+			tasks[r].DeletedAt = time.Time{}
+			restored = append(restored, tasks[r])
+		}
+
+		for p := indexOfAny(tasks, tasks[i].ParentID); p >= 0 && tasks[p].IsDeleted(); p = indexOfAny(tasks, tasks[p].ParentID) {
+			tasks[p].DeletedAt = time.Time{}
+			restored = append(restored, tasks[p])
+		}
+
+		return tasks, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// PurgeTasks permanently removes the tasks that have been in the trash for
+// at least olderThan, and drops references to them from other tasks.
+func PurgeTasks(store Store, olderThan time.Duration, now time.Time) ([]Task, error) {
+	var purged []Task
+	err := store.Update(func(tasks []Task) ([]Task, error) {
+		var IDs []int
+		for _, task := range tasks {
+			if task.IsDeleted() && !task.DeletedAt.After(now.Add(-olderThan)) {
+				IDs = append(IDs, task.ID)
+				purged = append(purged, task)
+			}
+		}
+
+		if len(IDs) == 0 {
+			return tasks, nil
+		}
+
+		tasks = slices.DeleteFunc(tasks, func(task Task) bool {
+			return slices.Contains(IDs, task.ID)
+		})
+
+		for i := range tasks {
+			if slices.Contains(IDs, tasks[i].ParentID) {
+				tasks[i].ParentID = 0
+			}
+			if slices.ContainsFunc(tasks[i].DependsOn, func(dep int) bool { return slices.Contains(IDs, dep) }) {
+				tasks[i].DependsOn = withoutIDs(tasks[i].DependsOn, IDs)
+			}
+		}
+
+		return tasks, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
+
+// deletedWith returns the index of tasks[i] followed by the indexes of its
+// trashed subtasks that were deleted at the same moment.
+func deletedWith(tasks []Task, i int) []int {
+	result := []int{i}
+	deletedAt := tasks[i].DeletedAt
+
+	for n := 0; n < len(result); n++ {
+		parent := tasks[result[n]].ID
+		for c, task := range tasks {
+			if task.ParentID == parent && task.DeletedAt.Equal(deletedAt) && !slices.Contains(result, c) {
+				result = append(result, c)
+			}
+		}
+	}
+
+	return result
+}
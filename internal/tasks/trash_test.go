@@ -0,0 +1,136 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func liveIDs(tasks []Task) []int {
+	var result []int
+	for _, task := range tasks {
+		if !task.IsDeleted() {
+			result = append(result, task.ID)
+		}
+	}
+
+	return result
+}
+
+func TestTrash(t *testing.T) {
+	store := NewMemoryStore(
+		Task{ID: 1, Status: "todo", Tags: []string{"home"}},
+		Task{ID: 2, Status: "todo", ParentID: 1},
+		Task{ID: 3, Status: "todo", DependsOn: []int{1}},
+		Task{ID: 4, Status: "todo"},
+	)
+
+	if _, err := DeleteTask(store, 1, ChildrenCascade); err != nil {
+		t.Fatalf("DeleteTask returned error: %v", err)
+	}
+
+	t.Run("Hides trashed tasks", func(t *testing.T) {
+		result, err := ListTasks(store, Filter{})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+		if !equalIDs(ids(result.Tasks), []int{3, 4}) || result.Total != 2 {
+			t.Errorf("Expected tasks [3 4] of 2, got %v of %d", ids(result.Tasks), result.Total)
+		}
+
+		if _, err := MarkTaskDone(store, 1, ChildrenRefuse); !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected ErrTaskNotFound for trashed task, got %v", err)
+		}
+
+		if counts, _ := TagCounts(store); len(counts) != 0 {
+			t.Errorf("Expected no tag counts, got %v", counts)
+		}
+	})
+
+	t.Run("Lists trashed tasks", func(t *testing.T) {
+		trashed, err := Trash(store)
+		if err != nil {
+			t.Fatalf("Trash returned error: %v", err)
+		}
+		if !equalIDs(ids(trashed), []int{1, 2}) {
+			t.Errorf("Expected trashed tasks [1 2], got %v", ids(trashed))
+		}
+	})
+
+	t.Run("Restores a task with the subtasks deleted along with it", func(t *testing.T) {
+		restored, err := RestoreTask(store, 1)
+		if err != nil {
+			t.Fatalf("RestoreTask returned error: %v", err)
+		}
+		if !equalIDs(ids(restored), []int{1, 2}) {
+			t.Errorf("Expected restored tasks [1 2], got %v", ids(restored))
+		}
+
+		tasks, _ := store.Load()
+		if !equalIDs(liveIDs(tasks), []int{1, 2, 3, 4}) {
+			t.Errorf("Expected all tasks back, got %v", liveIDs(tasks))
+		}
+
+		if _, err := RestoreTask(store, 4); !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected ErrTaskNotFound for task not in trash, got %v", err)
+		}
+	})
+
+	t.Run("Restores the trashed parents of a subtask", func(t *testing.T) {
+		store := NewMemoryStore(
+			Task{ID: 1, Status: "todo"},
+			Task{ID: 2, Status: "todo", ParentID: 1},
+			Task{ID: 3, Status: "todo", ParentID: 2},
+			Task{ID: 4, Status: "todo", ParentID: 2},
+		)
+		if _, err := DeleteTask(store, 1, ChildrenCascade); err != nil {
+			t.Fatalf("DeleteTask returned error: %v", err)
+		}
+
+		restored, err := RestoreTask(store, 3)
+		if err != nil {
+			t.Fatalf("RestoreTask returned error: %v", err)
+		}
+		if !equalIDs(ids(restored), []int{3, 2, 1}) {
+			t.Errorf("Expected restored tasks [3 2 1], got %v", ids(restored))
+		}
+
+		result, err := ListTasks(store, Filter{})
+		if err != nil {
+			t.Fatalf("ListTasks returned error: %v", err)
+		}
+		if got := ids(result.Tasks); !equalIDs(got, []int{1, 2, 3}) {
+			t.Errorf("Expected tasks [1 2 3] with task 4 still trashed, got %v", got)
+		}
+	})
+}
+
+func TestPurgeTasks(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(
+		Task{ID: 1, DeletedAt: now.Add(-40 * 24 * time.Hour)},
+		Task{ID: 2, DeletedAt: now.Add(-2 * time.Hour)},
+		Task{ID: 3, ParentID: 1, DependsOn: []int{1, 2}},
+	)
+
+	purged, err := PurgeTasks(store, 30*24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("PurgeTasks returned error: %v", err)
+	}
+	if !equalIDs(ids(purged), []int{1}) {
+		t.Errorf("Expected purged tasks [1], got %v", ids(purged))
+	}
+
+	task, _ := store.Get(3)
+	if task.ParentID != 0 || !equalIDs(task.DependsOn, []int{2}) {
+		t.Errorf("Expected references to task 1 dropped, got %+v", task)
+	}
+
+	if purged, _ := PurgeTasks(store, 0, now); !equalIDs(ids(purged), []int{2}) {
+		t.Errorf("Expected purged tasks [2], got %v", ids(purged))
+	}
+
+	if tasks, _ := store.Load(); !equalIDs(ids(tasks), []int{3}) {
+		t.Errorf("Expected only task 3 left, got %v", ids(tasks))
+	}
+}
@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+)
+
+var viewNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// View is a saved list command: the arguments it was saved with, such as a
+// query and --sort.
+type View struct {
+	Args []string `json:"args"`
+}
+
+// NormalizeViewName lower-cases a view name and checks that it is a single
+// word of letters, digits, "-" and "_".
+func NormalizeViewName(name string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+
+	if !viewNamePattern.MatchString(normalized) {
+		return "", &ValidationError{Field: "view", Message: fmt.Sprintf("invalid view name %q", name)}
+	}
+
+	return normalized, nil
+}
+
+// SaveView stores view under name, replacing a view of the same name. It
+// reports whether one was replaced.
+func SaveView(store Store, name string, view View) (bool, error) {
+	var replaced bool
+	err := store.UpdateMeta(func(tasks []Task, meta *Meta) ([]Task, error) {
+		_, replaced = meta.Views[name]
+
+		views := maps.Clone(meta.Views)
+		if views == nil {
+			views = map[string]View{}
+		}
+		views[name] = view
+		meta.Views = views
+
+		return tasks, nil
+	})
+
+	return replaced, err
+}
+
+// DeleteView removes a saved view.
+func DeleteView(store Store, name string) error {
+	return store.UpdateMeta(func(tasks []Task, meta *Meta) ([]Task, error) {
+		if _, ok := meta.Views[name]; !ok {
+			return nil, &ValidationError{Field: "view", Message: fmt.Sprintf("no saved view %q", name)}
+		}
+
+		views := maps.Clone(meta.Views)
+		delete(views, name)
+		meta.Views = views
+
+		return tasks, nil
+	})
+}
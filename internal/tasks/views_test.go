@@ -0,0 +1,76 @@
+package tasks
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeViewName(t *testing.T) {
+	if name, err := NormalizeViewName(" My-View_2 "); err != nil || name != "my-view_2" {
+		t.Errorf("Expected my-view_2, got %q (%v)", name, err)
+	}
+
+	for _, name := range []string{"", "-x", "two words", "a.b"} {
+		if _, err := NormalizeViewName(name); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected ErrValidation for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestViews(t *testing.T) {
+	t.Run("Saves, replaces and deletes views", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1})
+
+		if replaced, err := SaveView(store, "mine", View{Args: []string{"+urgent"}}); err != nil || replaced {
+			t.Fatalf("Expected a new view, got replaced=%v, err=%v", replaced, err)
+		}
+		if replaced, err := SaveView(store, "mine", View{Args: []string{"+next"}}); err != nil || !replaced {
+			t.Fatalf("Expected the view to be replaced, got replaced=%v, err=%v", replaced, err)
+		}
+
+		meta, _ := store.LoadMeta()
+		if !slices.Equal(meta.Views["mine"].Args, []string{"+next"}) {
+			t.Errorf("Expected args [+next], got %v", meta.Views["mine"].Args)
+		}
+
+		if err := DeleteView(store, "mine"); err != nil {
+			t.Fatalf("DeleteView returned error: %v", err)
+		}
+		if err := DeleteView(store, "mine"); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected ErrValidation for a missing view, got %v", err)
+		}
+	})
+
+	t.Run("Keeps views in the task file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "tasks.json")
+		if _, err := SaveView(NewJSONStore(filename), "today", View{Args: []string{"due<=today", "--sort", "due"}}); err != nil {
+			t.Fatalf("SaveView returned error: %v", err)
+		}
+
+		if _, err := AddTask(NewJSONStore(filename), "Buy groceries", AddOptions{}); err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+
+		meta, err := NewJSONStore(filename).LoadMeta()
+		if err != nil {
+			t.Fatalf("LoadMeta returned error: %v", err)
+		}
+		if !slices.Equal(meta.Views["today"].Args, []string{"due<=today", "--sort", "due"}) {
+			t.Errorf("Expected the saved view, got %v", meta.Views)
+		}
+	})
+
+	t.Run("Does not record an undo entry", func(t *testing.T) {
+		journal := newTestJournal(t)
+
+		if _, err := SaveView(journal.Wrap(NewMemoryStore(), "view save x"), "x", View{Args: []string{"+x"}}); err != nil {
+			t.Fatalf("SaveView returned error: %v", err)
+		}
+
+		if undo, _, _ := journal.Entries(); len(undo) != 0 {
+			t.Errorf("Expected no undo entries, got %+v", undo)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Workflow lists the statuses a task can have and which status changes are
+// allowed. Every workflow includes "done", which mark-done uses and which
+// always counts as finished.
+type Workflow struct {
+	States []string `json:"states"`
+	// Closed lists further statuses, such as "cancelled", that count as
+	// finished for dependencies, subtasks and overdue checks.
+	Closed []string `json:"closed,omitempty"`
+	// Initial is the status of new tasks; it defaults to the first state.
+	Initial string `json:"initial,omitempty"`
+	// Transitions maps a status to the statuses a task may move to from
+	// it. Without transitions any change is allowed; with them, a status
+	// that has no entry is final.
+	Transitions map[string][]string `json:"transitions,omitempty"`
+}
+
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		States:  []string{"todo", "in progress", "done"},
+		Initial: "todo",
+	}
+}
+
+// Validate checks that the workflow is consistent and fills in Initial.
+func (w *Workflow) Validate() error {
+	if len(w.States) == 0 {
+		return errWorkflow("workflow needs at least one state")
+	}
+
+	for i, state := range w.States {
+		if strings.TrimSpace(state) == "" {
+			return errWorkflow("workflow states cannot be empty")
+		}
+		if slices.Contains(w.States[:i], state) {
+			return errWorkflow(fmt.Sprintf("workflow state %q is listed twice", state))
+		}
+	}
+
+	if !w.HasState("done") {
+		return errWorkflow(`workflow must include the "done" state`)
+	}
+
+	for _, state := range w.Closed {
+		if !w.HasState(state) {
+			return errWorkflow(fmt.Sprintf("closed state %q is not a workflow state", state))
+		}
+	}
+
+	if w.Initial == "" {
+		w.Initial = w.States[0]
+	}
+	if !w.HasState(w.Initial) {
+		return errWorkflow(fmt.Sprintf("initial state %q is not a workflow state", w.Initial))
+	}
+
+	for from, targets := range w.Transitions {
+		if !w.HasState(from) {
+			return errWorkflow(fmt.Sprintf("transition from unknown state %q", from))
+		}
+		for _, to := range targets {
+			if !w.HasState(to) {
+				return errWorkflow(fmt.Sprintf("transition from %q to unknown state %q", from, to))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w Workflow) HasState(state string) bool {
+	return slices.Contains(w.States, state)
+}
+
+// IsClosed reports whether status counts as finished.
+func (w Workflow) IsClosed(status string) bool {
+	return status == "done" || slices.Contains(w.Closed, status)
+}
+
+// states returns the workflow states; the zero Workflow has the default ones.
+func (w Workflow) states() []string {
+	if len(w.States) == 0 {
+		return DefaultWorkflow().States
+	}
+
+	return w.States
+}
+
+// order is the position of status in the workflow, unknown statuses last.
+func (w Workflow) order(status string) int {
+	states := w.states()
+	if i := slices.Index(states, status); i >= 0 {
+		return i
+	}
+
+	return len(states)
+}
+
+// urgencyRank orders statuses for lists: open statuses furthest along the
+// workflow first, then unknown ones, then the closed ones in workflow order.
+func (w Workflow) urgencyRank(status string) int {
+	states := w.states()
+	i := slices.Index(states, status)
+	switch {
+	case i < 0:
+		return len(states)
+	case w.IsClosed(status):
+		return len(states) + 1 + i
+	default:
+		return len(states) - 1 - i
+	}
+}
+
+// checkState refuses a status that is not one of the workflow states.
+func (w Workflow) checkState(status string) error {
+	if !w.HasState(status) {
+		return &ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("unknown status %q (allowed: %s)", status, strings.Join(w.States, ", ")),
+		}
+	}
+
+	return nil
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Staying in the same status is always allowed.
+func (w Workflow) CanTransition(from, to string) bool {
+	if from == to || w.Transitions == nil {
+		return true
+	}
+
+	return slices.Contains(w.Transitions[from], to)
+}
+
+// checkTransition explains why a task cannot move to status, if it cannot.
+func (w Workflow) checkTransition(task Task, status string) error {
+	if err := w.checkState(status); err != nil {
+		return err
+	}
+
+	if !w.CanTransition(task.Status, status) {
+		allowed := "none"
+		if targets := w.Transitions[task.Status]; len(targets) > 0 {
+			allowed = strings.Join(targets, ", ")
+		}
+
+		return &ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("task %d cannot move from %q to %q (allowed: %s)", task.ID, task.Status, status, allowed),
+		}
+	}
+
+	return nil
+}
+
+func errWorkflow(message string) error {
+	return &ValidationError{Field: "workflow", Message: message}
+}
@@ -0,0 +1,209 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func reviewWorkflow(t *testing.T) Workflow {
+	t.Helper()
+
+	workflow := Workflow{
+		States: []string{"todo", "in progress", "review", "done", "cancelled"},
+		Closed: []string{"cancelled"},
+		Transitions: map[string][]string{
+			"todo":        {"in progress", "cancelled"},
+			"in progress": {"review", "todo"},
+			"review":      {"done", "in progress"},
+		},
+	}
+	if err := workflow.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	return workflow
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	invalid := map[string]Workflow{
+		"no states":            {},
+		"duplicate state":      {States: []string{"todo", "done", "todo"}},
+		"missing done":         {States: []string{"open", "closed"}},
+		"unknown initial":      {States: []string{"todo", "done"}, Initial: "new"},
+		"unknown source state": {States: []string{"todo", "done"}, Transitions: map[string][]string{"new": {"done"}}},
+		"unknown target state": {States: []string{"todo", "done"}, Transitions: map[string][]string{"todo": {"closed"}}},
+		"blank state":          {States: []string{"todo", " ", "done"}},
+		"unknown closed state": {States: []string{"todo", "done"}, Closed: []string{"cancelled"}},
+	}
+
+	for name, workflow := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := workflow.Validate(); !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+
+	t.Run("Defaults initial to the first state", func(t *testing.T) {
+		if workflow := reviewWorkflow(t); workflow.Initial != "todo" {
+			t.Errorf("Expected initial todo, got %q", workflow.Initial)
+		}
+	})
+}
+
+func TestClosedStates(t *testing.T) {
+	workflow := reviewWorkflow(t)
+	now := time.Now()
+
+	t.Run("Treats done and closed states as finished", func(t *testing.T) {
+		for status, want := range map[string]bool{"done": true, "cancelled": true, "review": false, "todo": false} {
+			if got := workflow.IsClosed(status); got != want {
+				t.Errorf("IsClosed(%q): expected %v, got %v", status, want, got)
+			}
+		}
+	})
+
+	t.Run("Unblocks dependents of closed tasks", func(t *testing.T) {
+		list := []Task{{ID: 1, Status: "cancelled"}, {ID: 2, Status: "todo", DependsOn: []int{1}}}
+
+		if blocking := BlockedBy(workflow, list, list[1]); len(blocking) != 0 {
+			t.Errorf("Expected task 2 not to be blocked, got %v", blocking)
+		}
+		if blocking := BlockedBy(Workflow{}, list, list[1]); len(blocking) != 1 {
+			t.Errorf("Expected the default workflow to keep task 2 blocked, got %v", blocking)
+		}
+	})
+
+	t.Run("Never reports closed tasks as overdue", func(t *testing.T) {
+		task := Task{ID: 1, Status: "cancelled", DueAt: now.Add(-time.Hour)}
+
+		if task.IsOverdue(workflow, now) {
+			t.Error("Expected a cancelled task not to be overdue")
+		}
+	})
+
+	t.Run("Ranks statuses by workflow order", func(t *testing.T) {
+		list := []Task{
+			{ID: 1, Status: "cancelled"},
+			{ID: 2, Status: "done"},
+			{ID: 3, Status: "todo"},
+			{ID: 4, Status: "review"},
+			{ID: 5, Status: "in progress"},
+			{ID: 6, Status: "unknown"},
+		}
+
+		SortByUrgency(workflow, list)
+		if got, want := ids(list), []int{4, 5, 3, 6, 2, 1}; !equalIDs(got, want) {
+			t.Errorf("Expected order %v, got %v", want, got)
+		}
+
+		SortTasks(workflow, list, []SortKey{{Field: "status"}})
+		if got, want := ids(list), []int{3, 5, 4, 2, 1, 6}; !equalIDs(got, want) {
+			t.Errorf("Expected order %v, got %v", want, got)
+		}
+	})
+
+	t.Run("Cascades the closed status to subtasks", func(t *testing.T) {
+		store := NewMemoryStore(
+			Task{ID: 1, Status: "todo"},
+			Task{ID: 2, Status: "todo", ParentID: 1},
+		)
+
+		if _, err := SetStatus(store, workflow, 1, "cancelled", StatusOptions{Children: ChildrenCascade}); err != nil {
+			t.Fatalf("SetStatus returned error: %v", err)
+		}
+
+		if child, _ := store.Get(2); child.Status != "cancelled" {
+			t.Errorf("Expected subtask to be cancelled, got %q", child.Status)
+		}
+	})
+}
+
+func TestSetStatus(t *testing.T) {
+	workflow := reviewWorkflow(t)
+
+	t.Run("Follows allowed transitions", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"})
+
+		for _, status := range []string{"in progress", "review", "done"} {
+			task, err := SetStatus(store, workflow, 1, status, StatusOptions{})
+			if err != nil {
+				t.Fatalf("SetStatus(%q) returned error: %v", status, err)
+			}
+			if task.Status != status {
+				t.Errorf("Expected status %q, got %q", status, task.Status)
+			}
+		}
+	})
+
+	t.Run("Refuses disallowed transitions", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"})
+
+		if _, err := SetStatus(store, workflow, 1, "done", StatusOptions{}); !errors.Is(err, ErrValidation) {
+			t.Fatalf("Expected validation error, got %v", err)
+		}
+
+		task, _ := store.Get(1)
+		if task.Status != "todo" {
+			t.Errorf("Expected status to stay todo, got %q", task.Status)
+		}
+	})
+
+	t.Run("Treats states without transitions as final", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "cancelled"})
+
+		if _, err := SetStatus(store, workflow, 1, "todo", StatusOptions{}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("Refuses unknown statuses", func(t *testing.T) {
+		store := NewMemoryStore(Task{ID: 1, Status: "todo"})
+
+		if _, err := SetStatus(store, workflow, 1, "blocked", StatusOptions{}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("Checks transitions of cascaded subtasks", func(t *testing.T) {
+		store := NewMemoryStore(
+			Task{ID: 1, Status: "review"},
+			Task{ID: 2, Status: "todo", ParentID: 1},
+		)
+
+		if _, err := SetStatus(store, workflow, 1, "done", StatusOptions{Children: ChildrenCascade}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("Adds tasks in the initial state", func(t *testing.T) {
+		store := NewMemoryStore()
+		workflow := Workflow{States: []string{"backlog", "todo", "done"}}
+		if err := workflow.Validate(); err != nil {
+			t.Fatalf("Validate returned error: %v", err)
+		}
+
+		task, err := AddTask(store, "Write docs", AddOptions{Workflow: workflow})
+		if err != nil {
+			t.Fatalf("AddTask returned error: %v", err)
+		}
+		if task.Status != "backlog" {
+			t.Errorf("Expected status backlog, got %q", task.Status)
+		}
+	})
+
+	t.Run("Refuses to add tasks in an unknown status", func(t *testing.T) {
+		store := NewMemoryStore()
+
+		if _, err := AddTask(store, "Write docs", AddOptions{Status: "backlog", Workflow: workflow}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+		if _, err := AddTask(store, "Write docs", AddOptions{Status: "backlog"}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected validation error with the default workflow, got %v", err)
+		}
+		if list, _ := store.Load(); len(list) != 0 {
+			t.Errorf("Expected no task to be added, got %v", list)
+		}
+	})
+}